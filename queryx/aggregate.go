@@ -0,0 +1,165 @@
+package queryx
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Aggregate is a call to an aggregate function, for use with SelectExpr or
+// as an argument of Having. Its methods return modified copies:
+//
+//	qb.SelectExpr(queryx.Count("*").Filter("status = ?", []any{"paid"}), "paid")
+//
+// Over computes the aggregate over a window instead of a group.
+type Aggregate struct {
+	name      string
+	column    string
+	separator string
+	distinct  bool
+	orderBy   []string
+	condition string
+	args      []any
+}
+
+// Count counts the rows, with "*", or the non-NULL values of column.
+func Count(column string) Aggregate {
+	return Aggregate{name: "COUNT", column: column}
+}
+
+// Sum adds up the values of column.
+func Sum(column string) Aggregate {
+	return Aggregate{name: "SUM", column: column}
+}
+
+// Avg averages the values of column.
+func Avg(column string) Aggregate {
+	return Aggregate{name: "AVG", column: column}
+}
+
+// Min returns the smallest value of column.
+func Min(column string) Aggregate {
+	return Aggregate{name: "MIN", column: column}
+}
+
+// Max returns the largest value of column.
+func Max(column string) Aggregate {
+	return Aggregate{name: "MAX", column: column}
+}
+
+// StringAgg concatenates the values of column with separator. It renders
+// STRING_AGG, GROUP_CONCAT on MySQL and LISTAGG on Oracle.
+func StringAgg(column, separator string) Aggregate {
+	return Aggregate{name: "STRING_AGG", column: column, separator: separator}
+}
+
+// ArrayAgg collects the values of column into an array. Only Postgres
+// supports it.
+func ArrayAgg(column string) Aggregate {
+	return Aggregate{name: "ARRAY_AGG", column: column}
+}
+
+// Distinct aggregates each distinct value once.
+func (a Aggregate) Distinct() Aggregate {
+	a.distinct = true
+	return a
+}
+
+// OrderBy orders the values concatenated by StringAgg or collected by
+// ArrayAgg, with terms written as for QueryBuilder.OrderBy. SQL Server and
+// Oracle render it as WITHIN GROUP (ORDER BY ...).
+func (a Aggregate) OrderBy(terms ...string) Aggregate {
+	a.orderBy = append(slices.Clip(a.orderBy), terms...)
+	return a
+}
+
+// Filter aggregates only the rows matching condition, written with "?"
+// placeholders as for Where: "COUNT(*) FILTER (WHERE condition)". Dialects
+// without FILTER get "COUNT(CASE WHEN condition THEN 1 END)", which drops
+// the other rows the same way.
+func (a Aggregate) Filter(condition string, args []any) Aggregate {
+	a.condition, a.args = condition, args
+	return a
+}
+
+// FilterCond is Filter with a condition built with the Cond API.
+func (a Aggregate) FilterCond(cond Cond) Aggregate {
+	return a.Filter("?", []any{cond})
+}
+
+// Over computes a over the window described by parts.
+func (a Aggregate) Over(parts ...WindowPart) Expr {
+	return windowExpr{call: a, spec: newWindowSpec(parts)}
+}
+
+// OverWindow computes a over a window declared with QueryBuilder.Window.
+func (a Aggregate) OverWindow(name string) Expr {
+	return windowExpr{call: a, name: name}
+}
+
+func (a Aggregate) appendSQL(qb *QueryBuilder, b *strings.Builder, args []any) ([]any, error) {
+	d := qb.getDialect()
+	name := a.name
+	switch {
+	case name == "ARRAY_AGG" && !d.Supports(FeatureArrayAgg):
+		return nil, fmt.Errorf("ARRAY_AGG is not supported by the %s dialect", d.Name())
+	case name == "STRING_AGG" && d.Supports(FeatureGroupConcat):
+		name = "GROUP_CONCAT"
+	case name == "STRING_AGG" && d.Supports(FeatureListAgg):
+		name = "LISTAGG"
+	}
+	withinGroup := a.name == "STRING_AGG" && d.Supports(FeatureWithinGroup)
+
+	b.WriteString(name + "(")
+	if a.distinct {
+		b.WriteString("DISTINCT ")
+	}
+	filter := a.condition != "" && d.Supports(FeatureAggregateFilter)
+	column := quoteColumn(qb, a.column)
+	if a.condition != "" && !filter {
+		if column == "*" {
+			column = "1"
+		}
+		var err error
+		b.WriteString("CASE WHEN ")
+		if args, err = appendExpr(qb, b, a.condition, a.args, args); err != nil {
+			return nil, err
+		}
+		b.WriteString(" THEN " + column + " END")
+	} else {
+		b.WriteString(column)
+	}
+
+	separator := "'" + strings.ReplaceAll(a.separator, "'", "''") + "'"
+	if a.name == "STRING_AGG" && name != "GROUP_CONCAT" {
+		b.WriteString(", " + separator)
+	}
+	orderBy := ""
+	if len(a.orderBy) > 0 {
+		terms := make([]string, len(a.orderBy))
+		for i, term := range a.orderBy {
+			terms[i] = quoteOrderBy(qb, term)
+		}
+		orderBy = "ORDER BY " + strings.Join(terms, ", ")
+	}
+	if orderBy != "" && !withinGroup {
+		b.WriteString(" " + orderBy)
+	}
+	if name == "GROUP_CONCAT" {
+		b.WriteString(" SEPARATOR " + separator)
+	}
+	b.WriteString(")")
+	if orderBy != "" && withinGroup {
+		b.WriteString(" WITHIN GROUP (" + orderBy + ")")
+	}
+
+	if filter {
+		var err error
+		b.WriteString(" FILTER (WHERE ")
+		if args, err = appendExpr(qb, b, a.condition, a.args, args); err != nil {
+			return nil, err
+		}
+		b.WriteString(")")
+	}
+	return args, nil
+}
@@ -0,0 +1,176 @@
+package queryx
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAggregates(t *testing.T) {
+	cases := []struct {
+		name         string
+		qb           *QueryBuilder
+		expectedExpr string
+		expectedArgs []any
+	}{
+		{
+			"filter",
+			NewQuery().
+				WithDialect(Postgres).
+				Select("customer_id").
+				SelectExpr(Count("*"), "orders").
+				SelectExpr(Sum("total").Filter("status = ?", []any{"paid"}), "paid").
+				SelectExpr(Count("product_id").Distinct().FilterCond(Gt("quantity", 1)), "bulk_products").
+				From("orders").
+				Where("created_at > ?", []any{"2024-01-01"}).
+				GroupBy("customer_id").
+				Having("? > ?", []any{Avg("total"), 100}),
+			"SELECT customer_id, COUNT(*) AS orders, SUM(total) FILTER (WHERE status = $1) AS paid, COUNT(DISTINCT product_id) FILTER (WHERE quantity > $2) AS bulk_products FROM orders WHERE created_at > $3 GROUP BY customer_id HAVING AVG(total) > $4",
+			[]any{"paid", 1, "2024-01-01", 100},
+		},
+		{
+			"filter emulated with case",
+			NewQuery().
+				WithDialect(MySQL).
+				QuoteIdentifiers().
+				Select("customer_id").
+				SelectExpr(Count("*").Filter("status = ?", []any{"paid"}), "paid_orders").
+				SelectExpr(Max("total").FilterCond(Eq("status", "refunded")), "largest_refund").
+				From("orders").
+				GroupBy("customer_id"),
+			"SELECT `customer_id`, COUNT(CASE WHEN status = ? THEN 1 END) AS `paid_orders`, MAX(CASE WHEN `status` = ? THEN `total` END) AS `largest_refund` FROM `orders` GROUP BY `customer_id`",
+			[]any{"paid", "refunded"},
+		},
+		{
+			"string and array aggregation",
+			NewQuery().
+				WithDialect(Postgres).
+				Select("team_id").
+				SelectExpr(StringAgg("name", ", ").OrderBy("name"), "names").
+				SelectExpr(ArrayAgg("id").Distinct().OrderBy("id DESC"), "ids").
+				SelectExpr(Min("joined_at"), "first_joined").
+				From("members").
+				GroupBy("team_id"),
+			"SELECT team_id, STRING_AGG(name, ', ' ORDER BY name) AS names, ARRAY_AGG(DISTINCT id ORDER BY id DESC) AS ids, MIN(joined_at) AS first_joined FROM members GROUP BY team_id",
+			nil,
+		},
+		{
+			"group concat",
+			NewQuery().
+				WithDialect(MySQL).
+				Select("team_id").
+				SelectExpr(StringAgg("name", "'; '").Distinct().OrderBy("name"), "names").
+				From("members").
+				GroupBy("team_id"),
+			"SELECT team_id, GROUP_CONCAT(DISTINCT name ORDER BY name SEPARATOR '''; ''') AS names FROM members GROUP BY team_id",
+			nil,
+		},
+		{
+			"within group",
+			NewQuery().
+				WithDialect(SQLServer).
+				Select("team_id").
+				SelectExpr(StringAgg("name", ",").OrderBy("name").Filter("active = ?", []any{true}), "names").
+				From("members").
+				GroupBy("team_id"),
+			"SELECT team_id, STRING_AGG(CASE WHEN active = @p1 THEN name END, ',') WITHIN GROUP (ORDER BY name) AS names FROM members GROUP BY team_id",
+			[]any{true},
+		},
+		{
+			"listagg",
+			NewQuery().
+				WithDialect(Oracle).
+				Select("team_id").
+				SelectExpr(StringAgg("name", ",").OrderBy("name"), "names").
+				From("members").
+				GroupBy("team_id"),
+			"SELECT team_id, LISTAGG(name, ',') WITHIN GROUP (ORDER BY name) AS names FROM members GROUP BY team_id",
+			nil,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			expr, args, err := c.qb.Build()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if expr != c.expectedExpr {
+				t.Errorf("expected SQL:\n%s\ngot:\n%s", c.expectedExpr, expr)
+			}
+			if !reflect.DeepEqual(args, c.expectedArgs) {
+				t.Errorf("expected args %v, got %v", c.expectedArgs, args)
+			}
+		})
+	}
+}
+
+func TestGroupingSets(t *testing.T) {
+	sales := func(d Dialect) *QueryBuilder {
+		return NewQuery().
+			WithDialect(d).
+			Select("region", "product").
+			SelectExpr(Sum("amount"), "total").
+			From("sales")
+	}
+
+	cases := []struct {
+		name         string
+		qb           *QueryBuilder
+		expectedExpr string
+	}{
+		{
+			"rollup",
+			sales(Postgres).GroupByRollup("region", "product"),
+			"SELECT region, product, SUM(amount) AS total FROM sales GROUP BY ROLLUP (region, product)",
+		},
+		{
+			"with rollup",
+			sales(MySQL).GroupByRollup("region", "product"),
+			"SELECT region, product, SUM(amount) AS total FROM sales GROUP BY region, product WITH ROLLUP",
+		},
+		{
+			"cube",
+			sales(Oracle).GroupByCube("region", "product"),
+			"SELECT region, product, SUM(amount) AS total FROM sales GROUP BY CUBE (region, product)",
+		},
+		{
+			"grouping sets",
+			sales(SQLServer).QuoteIdentifiers().GroupingSets([]string{"region", "product"}, []string{"region"}, nil),
+			"SELECT [region], [product], SUM([amount]) AS [total] FROM [sales] GROUP BY GROUPING SETS (([region], [product]), ([region]), ())",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			expr, _, err := c.qb.Build()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if expr != c.expectedExpr {
+				t.Errorf("expected SQL:\n%s\ngot:\n%s", c.expectedExpr, expr)
+			}
+		})
+	}
+
+	count, _, err := sales(Postgres).GroupingSets([]string{"region"}, nil).CountTotal().Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := "SELECT COUNT(*) FROM (SELECT 1 FROM sales GROUP BY GROUPING SETS ((region), ())) AS subquery"
+	if count != expected {
+		t.Errorf("expected SQL:\n%s\ngot:\n%s", expected, count)
+	}
+}
+
+func TestAggregates_Unsupported(t *testing.T) {
+	cases := []*QueryBuilder{
+		NewQuery().WithDialect(MySQL).Select("region").From("sales").GroupByCube("region"),
+		NewQuery().WithDialect(SQLite).Select("region").From("sales").GroupByRollup("region"),
+		NewQuery().WithDialect(MySQL).SelectExpr(ArrayAgg("id"), "ids").From("sales"),
+	}
+	for _, qb := range cases {
+		if _, _, err := qb.Build(); err == nil {
+			t.Errorf("%s: expected an error", qb.getDialect().Name())
+		}
+	}
+}
@@ -2,8 +2,14 @@ package clauses
 
 type From struct {
 	Table string
+	Alias string
+	Args  []any
 }
 
 func NewFrom(table string) *From {
 	return &From{Table: table}
 }
+
+func NewFromSubquery(query any, alias string) *From {
+	return &From{Table: "(?)", Alias: alias, Args: []any{query}}
+}
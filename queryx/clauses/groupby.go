@@ -1,9 +1,29 @@
 package clauses
 
+const (
+	Rollup       = "ROLLUP"
+	Cube         = "CUBE"
+	GroupingSets = "GROUPING SETS"
+)
+
 type GroupBy struct {
+	Type    string
 	Columns []string
+	Sets    [][]string
 }
 
 func NewGroupBy(columns ...string) *GroupBy {
 	return &GroupBy{Columns: columns}
 }
+
+func NewGroupByRollup(columns ...string) *GroupBy {
+	return &GroupBy{Type: Rollup, Columns: columns}
+}
+
+func NewGroupByCube(columns ...string) *GroupBy {
+	return &GroupBy{Type: Cube, Columns: columns}
+}
+
+func NewGroupingSets(sets ...[]string) *GroupBy {
+	return &GroupBy{Type: GroupingSets, Sets: sets}
+}
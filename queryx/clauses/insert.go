@@ -8,3 +8,11 @@ type Insert struct {
 func NewInsert(table string, columns []string) *Insert {
 	return &Insert{Table: table, Columns: columns}
 }
+
+type InsertSelect struct {
+	Query any
+}
+
+func NewInsertSelect(query any) *InsertSelect {
+	return &InsertSelect{Query: query}
+}
@@ -3,11 +3,18 @@ package clauses
 const (
 	LeftJoin  = "LEFT JOIN"
 	InnerJoin = "INNER JOIN"
+	RightJoin = "RIGHT JOIN"
+	FullJoin  = "FULL OUTER JOIN"
+	CrossJoin = "CROSS JOIN"
 )
 
 type Join struct {
 	Type      string
+	Lateral   bool
 	Table     string
+	Alias     string
+	TableArgs []any
+	Using     []string
 	Condition string
 	Args      []any
 }
@@ -19,3 +26,36 @@ func NewInnerJoin(table string, condition string, args []any) *Join {
 func NewLeftJoin(table string, condition string, args []any) *Join {
 	return &Join{Type: LeftJoin, Table: table, Condition: condition, Args: args}
 }
+
+func NewRightJoin(table string, condition string, args []any) *Join {
+	return &Join{Type: RightJoin, Table: table, Condition: condition, Args: args}
+}
+
+func NewFullJoin(table string, condition string, args []any) *Join {
+	return &Join{Type: FullJoin, Table: table, Condition: condition, Args: args}
+}
+
+func NewCrossJoin(table string) *Join {
+	return &Join{Type: CrossJoin, Table: table}
+}
+
+func NewJoinUsing(joinType string, table string, columns []string) *Join {
+	return &Join{Type: joinType, Table: table, Using: columns}
+}
+
+func NewJoinSubquery(joinType string, query any, alias, condition string, args []any) *Join {
+	return &Join{
+		Type:      joinType,
+		Table:     "(?)",
+		Alias:     alias,
+		TableArgs: []any{query},
+		Condition: condition,
+		Args:      args,
+	}
+}
+
+func NewLateralJoin(joinType string, query any, alias, condition string, args []any) *Join {
+	join := NewJoinSubquery(joinType, query, alias, condition, args)
+	join.Lateral = true
+	return join
+}
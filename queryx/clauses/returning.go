@@ -0,0 +1,9 @@
+package clauses
+
+type Returning struct {
+	Columns []string
+}
+
+func NewReturning(columns []string) *Returning {
+	return &Returning{Columns: columns}
+}
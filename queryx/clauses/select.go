@@ -2,6 +2,7 @@ package clauses
 
 type Select struct {
 	Columns []string
+	Args    []any
 }
 
 func NewSelect(columns ...string) *Select {
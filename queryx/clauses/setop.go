@@ -0,0 +1,17 @@
+package clauses
+
+const (
+	Union     = "UNION"
+	UnionAll  = "UNION ALL"
+	Intersect = "INTERSECT"
+	Except    = "EXCEPT"
+)
+
+type SetOperation struct {
+	Op    string
+	Query any
+}
+
+func NewSetOperation(op string, query any) *SetOperation {
+	return &SetOperation{Op: op, Query: query}
+}
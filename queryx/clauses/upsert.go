@@ -0,0 +1,31 @@
+package clauses
+
+type Assignment struct {
+	Column string
+	Expr   string
+	Args   []any
+}
+
+type OnConflict struct {
+	Columns   []string
+	DoNothing bool
+	Set       []*Assignment
+	Condition string
+	Args      []any
+}
+
+type OnDuplicateKey struct {
+	Set []*Assignment
+}
+
+func NewAssignment(column, expr string, args []any) *Assignment {
+	return &Assignment{Column: column, Expr: expr, Args: args}
+}
+
+func NewOnConflict(columns []string) *OnConflict {
+	return &OnConflict{Columns: columns}
+}
+
+func NewOnDuplicateKey() *OnDuplicateKey {
+	return &OnDuplicateKey{}
+}
@@ -0,0 +1,23 @@
+package clauses
+
+type With struct {
+	Name      string
+	Columns   []string
+	Recursive bool
+	Query     string
+	Args      []any
+}
+
+func NewWith(name string, query any) *With {
+	return &With{Name: name, Query: "?", Args: []any{query}}
+}
+
+func NewWithRecursive(name string, columns []string, anchor, recursive any) *With {
+	return &With{
+		Name:      name,
+		Columns:   columns,
+		Recursive: true,
+		Query:     "? UNION ALL ?",
+		Args:      []any{anchor, recursive},
+	}
+}
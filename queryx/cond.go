@@ -0,0 +1,368 @@
+package queryx
+
+import (
+	"errors"
+	"strings"
+)
+
+// Cond is a composable SQL condition. Conditions are rendered at Build time,
+// so they follow the builder's dialect and identifier quoting.
+//
+// A Cond can be passed to WhereCond, HavingCond and JoinCond, or used as an
+// argument of a raw condition, in which case it replaces its "?"
+// placeholder:
+//
+//	qb.Where("tenant_id = ? AND ?", []any{7, queryx.Or(
+//	    queryx.Eq("status", "open"),
+//	    queryx.Gt("priority", 2),
+//	)})
+type Cond interface {
+	appendSQL(qb *QueryBuilder, b *strings.Builder, args []any) ([]any, error)
+}
+
+// Operand contexts used to decide whether a nested condition needs parentheses.
+const (
+	opAnd      = "AND"
+	opOr       = "OR"
+	opEmbedded = "?"
+)
+
+// Eq renders "column = ?", or "column IS NULL" when value is nil.
+func Eq(column string, value any) Cond {
+	return compareCond{column: column, op: "=", value: value}
+}
+
+// NotEq renders "column <> ?", or "column IS NOT NULL" when value is nil.
+func NotEq(column string, value any) Cond {
+	return compareCond{column: column, op: "<>", value: value}
+}
+
+// Gt renders "column > ?".
+func Gt(column string, value any) Cond {
+	return compareCond{column: column, op: ">", value: value}
+}
+
+// Gte renders "column >= ?".
+func Gte(column string, value any) Cond {
+	return compareCond{column: column, op: ">=", value: value}
+}
+
+// Lt renders "column < ?".
+func Lt(column string, value any) Cond {
+	return compareCond{column: column, op: "<", value: value}
+}
+
+// Lte renders "column <= ?".
+func Lte(column string, value any) Cond {
+	return compareCond{column: column, op: "<=", value: value}
+}
+
+// In renders "column IN (?, ?, ...)". A single slice argument is expanded
+// into its elements, and a single *QueryBuilder renders
+// "column IN (SELECT ...)". With no values it renders "1=0", which matches
+// no rows.
+func In(column string, values ...any) Cond {
+	return inCond{column: column, values: flattenValues(values)}
+}
+
+// NotIn renders "column NOT IN (?, ?, ...)". A single slice argument is
+// expanded into its elements. With no values it renders "1=1", which
+// matches every row.
+func NotIn(column string, values ...any) Cond {
+	return inCond{column: column, values: flattenValues(values), not: true}
+}
+
+// Between renders "column BETWEEN ? AND ?".
+func Between(column string, low, high any) Cond {
+	return betweenCond{column: column, low: low, high: high}
+}
+
+// Exists renders "EXISTS (subquery)".
+func Exists(sub *QueryBuilder) Cond {
+	return existsCond{sub: sub}
+}
+
+// NotExists renders "NOT EXISTS (subquery)".
+func NotExists(sub *QueryBuilder) Cond {
+	return existsCond{sub: sub, not: true}
+}
+
+// IsNull renders "column IS NULL".
+func IsNull(column string) Cond {
+	return nullCond{column: column}
+}
+
+// IsNotNull renders "column IS NOT NULL".
+func IsNotNull(column string) Cond {
+	return nullCond{column: column, not: true}
+}
+
+// And joins conditions with AND. Nil conditions are skipped, and an empty
+// And renders "1=1".
+func And(conds ...Cond) Cond {
+	return junction{op: opAnd, conds: conds}
+}
+
+// Or joins conditions with OR. Nil conditions are skipped, and an empty Or
+// renders "1=0".
+func Or(conds ...Cond) Cond {
+	return junction{op: opOr, conds: conds}
+}
+
+// Not renders "NOT (cond)". A nil cond makes Build fail.
+func Not(cond Cond) Cond {
+	return notCond{cond: cond}
+}
+
+// Raw renders a hand written condition with "?" placeholders.
+func Raw(sql string, args ...any) Cond {
+	return rawCond{sql: sql, args: args}
+}
+
+type compareCond struct {
+	column string
+	op     string
+	value  any
+}
+
+func (c compareCond) appendSQL(qb *QueryBuilder, b *strings.Builder, args []any) ([]any, error) {
+	b.WriteString(quoteColumn(qb, c.column))
+	if c.value == nil && (c.op == "=" || c.op == "<>") {
+		if c.op == "=" {
+			b.WriteString(" IS NULL")
+		} else {
+			b.WriteString(" IS NOT NULL")
+		}
+		return args, nil
+	}
+	b.WriteString(" " + c.op + " ")
+	return appendValue(qb, b, c.value, args)
+}
+
+type inCond struct {
+	column string
+	values []any
+	not    bool
+}
+
+func (c inCond) appendSQL(qb *QueryBuilder, b *strings.Builder, args []any) ([]any, error) {
+	if len(c.values) == 0 {
+		if c.not {
+			b.WriteString("1=1")
+		} else {
+			b.WriteString("1=0")
+		}
+		return args, nil
+	}
+
+	b.WriteString(quoteColumn(qb, c.column))
+	if c.not {
+		b.WriteString(" NOT IN (")
+	} else {
+		b.WriteString(" IN (")
+	}
+	if sub, ok := c.values[0].(*QueryBuilder); ok && len(c.values) == 1 {
+		args, err := appendSubquery(qb, b, sub, args)
+		if err != nil {
+			return nil, err
+		}
+		b.WriteString(")")
+		return args, nil
+	}
+
+	var err error
+	for i, v := range c.values {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		if args, err = appendValue(qb, b, v, args); err != nil {
+			return nil, err
+		}
+	}
+	b.WriteString(")")
+	return args, nil
+}
+
+type betweenCond struct {
+	column    string
+	low, high any
+}
+
+func (c betweenCond) appendSQL(qb *QueryBuilder, b *strings.Builder, args []any) ([]any, error) {
+	b.WriteString(quoteColumn(qb, c.column))
+	b.WriteString(" BETWEEN ")
+	args, err := appendValue(qb, b, c.low, args)
+	if err != nil {
+		return nil, err
+	}
+	b.WriteString(" AND ")
+	return appendValue(qb, b, c.high, args)
+}
+
+type existsCond struct {
+	sub *QueryBuilder
+	not bool
+}
+
+func (c existsCond) appendSQL(qb *QueryBuilder, b *strings.Builder, args []any) ([]any, error) {
+	if c.not {
+		b.WriteString("NOT ")
+	}
+	b.WriteString("EXISTS (")
+	args, err := appendSubquery(qb, b, c.sub, args)
+	if err != nil {
+		return nil, err
+	}
+	b.WriteString(")")
+	return args, nil
+}
+
+type nullCond struct {
+	column string
+	not    bool
+}
+
+func (c nullCond) appendSQL(qb *QueryBuilder, b *strings.Builder, args []any) ([]any, error) {
+	b.WriteString(quoteColumn(qb, c.column))
+	if c.not {
+		b.WriteString(" IS NOT NULL")
+	} else {
+		b.WriteString(" IS NULL")
+	}
+	return args, nil
+}
+
+type junction struct {
+	op    string
+	conds []Cond
+}
+
+// compact returns the non-nil conditions of the junction.
+func (j junction) compact() []Cond {
+	conds := make([]Cond, 0, len(j.conds))
+	for _, c := range j.conds {
+		if c != nil {
+			conds = append(conds, c)
+		}
+	}
+	return conds
+}
+
+func (j junction) appendSQL(qb *QueryBuilder, b *strings.Builder, args []any) ([]any, error) {
+	conds := j.compact()
+	if len(conds) == 0 {
+		if j.op == opAnd {
+			b.WriteString("1=1")
+		} else {
+			b.WriteString("1=0")
+		}
+		return args, nil
+	}
+	if len(conds) == 1 {
+		return conds[0].appendSQL(qb, b, args)
+	}
+
+	var err error
+	for i, c := range conds {
+		if i > 0 {
+			b.WriteString(" " + j.op + " ")
+		}
+		if args, err = appendCond(qb, b, c, j.op, args); err != nil {
+			return nil, err
+		}
+	}
+	return args, nil
+}
+
+type notCond struct {
+	cond Cond
+}
+
+func (c notCond) appendSQL(qb *QueryBuilder, b *strings.Builder, args []any) ([]any, error) {
+	if c.cond == nil {
+		return nil, errors.New("Not: nil condition")
+	}
+	b.WriteString("NOT (")
+	args, err := c.cond.appendSQL(qb, b, args)
+	if err != nil {
+		return nil, err
+	}
+	b.WriteString(")")
+	return args, nil
+}
+
+type rawCond struct {
+	sql  string
+	args []any
+}
+
+func (c rawCond) appendSQL(qb *QueryBuilder, b *strings.Builder, args []any) ([]any, error) {
+	return appendExpr(qb, b, c.sql, c.args, args)
+}
+
+// appendCond renders c as an operand of op, which is opAnd, opOr, opEmbedded
+// or empty when the condition stands alone, wrapping it in parentheses when
+// operator precedence requires it.
+func appendCond(qb *QueryBuilder, b *strings.Builder, c Cond, op string, args []any) ([]any, error) {
+	if s, ok := c.(seekCond); ok {
+		c = s.expand(qb.getDialect())
+	}
+	if !condNeedsParens(c, op) {
+		return c.appendSQL(qb, b, args)
+	}
+
+	b.WriteString("(")
+	args, err := c.appendSQL(qb, b, args)
+	if err != nil {
+		return nil, err
+	}
+	b.WriteString(")")
+	return args, nil
+}
+
+func condNeedsParens(c Cond, op string) bool {
+	if op == "" {
+		return false
+	}
+
+	switch c := c.(type) {
+	case junction:
+		conds := c.compact()
+		if len(conds) == 1 {
+			return condNeedsParens(conds[0], op)
+		}
+		return len(conds) > 1 && c.op != op
+	case rawCond:
+		return true
+	}
+	return false
+}
+
+// flattenValues expands a single slice argument, as in In("id", ids).
+func flattenValues(values []any) []any {
+	if len(values) == 1 {
+		if elems, ok := sliceArgs(values[0]); ok {
+			return elems
+		}
+	}
+	return values
+}
+
+// appendValue writes a single operand as a placeholder and records its
+// argument. A *QueryBuilder operand is rendered as a parenthesized scalar
+// subquery.
+func appendValue(qb *QueryBuilder, b *strings.Builder, v any, args []any) ([]any, error) {
+	sub, ok := v.(*QueryBuilder)
+	if !ok {
+		b.WriteString("?")
+		return append(args, v), nil
+	}
+
+	b.WriteString("(")
+	args, err := appendSubquery(qb, b, sub, args)
+	if err != nil {
+		return nil, err
+	}
+	b.WriteString(")")
+	return args, nil
+}
@@ -0,0 +1,210 @@
+package queryx
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCond_Render(t *testing.T) {
+	cases := []struct {
+		name         string
+		cond         Cond
+		expectedExpr string
+		expectedArgs []any
+	}{
+		{"eq", Eq("a", 1), "a = ?", []any{1}},
+		{"eq nil", Eq("a", nil), "a IS NULL", nil},
+		{"not eq", NotEq("a", 1), "a <> ?", []any{1}},
+		{"not eq nil", NotEq("a", nil), "a IS NOT NULL", nil},
+		{"gt", Gt("a", 1), "a > ?", []any{1}},
+		{"gte", Gte("a", 1), "a >= ?", []any{1}},
+		{"lt", Lt("a", 1), "a < ?", []any{1}},
+		{"lte", Lte("a", 1), "a <= ?", []any{1}},
+		{"in", In("a", 1, 2, 3), "a IN (?, ?, ?)", []any{1, 2, 3}},
+		{"in empty", In("a"), "1=0", nil},
+		{"in slice", In("a", []int{1, 2}), "a IN (?, ?)", []any{1, 2}},
+		{"in empty slice", In("a", []int{}), "1=0", nil},
+		{"not in empty slice", NotIn("a", []string{}), "1=1", nil},
+		{"not in", NotIn("a", 1, 2), "a NOT IN (?, ?)", []any{1, 2}},
+		{"not in empty", NotIn("a"), "1=1", nil},
+		{"between", Between("a", 1, 5), "a BETWEEN ? AND ?", []any{1, 5}},
+		{"is null", IsNull("a"), "a IS NULL", nil},
+		{"is not null", IsNotNull("a"), "a IS NOT NULL", nil},
+		{"not", Not(Eq("a", 1)), "NOT (a = ?)", []any{1}},
+		{"raw", Raw("lower(a) = ?", "x"), "lower(a) = ?", []any{"x"}},
+		{"empty and", And(), "1=1", nil},
+		{"empty or", Or(), "1=0", nil},
+		{"single and", And(nil, Eq("a", 1)), "a = ?", []any{1}},
+		{
+			"or inside and",
+			And(Or(Eq("a", 1), Eq("b", 2)), Eq("c", 3)),
+			"(a = ? OR b = ?) AND c = ?",
+			[]any{1, 2, 3},
+		},
+		{
+			"and inside or",
+			Or(And(Eq("a", 1), Eq("b", 2)), Eq("c", 3)),
+			"(a = ? AND b = ?) OR c = ?",
+			[]any{1, 2, 3},
+		},
+		{
+			"nested same operator",
+			And(And(Eq("a", 1), Eq("b", 2)), Eq("c", 3)),
+			"a = ? AND b = ? AND c = ?",
+			[]any{1, 2, 3},
+		},
+		{
+			"raw inside and",
+			And(Raw("a = ? OR b = ?", 1, 2), Eq("c", 3)),
+			"(a = ? OR b = ?) AND c = ?",
+			[]any{1, 2, 3},
+		},
+		{
+			"not or",
+			Not(Or(Eq("a", 1), Eq("b", 2))),
+			"NOT (a = ? OR b = ?)",
+			[]any{1, 2},
+		},
+	}
+
+	for _, c := range cases {
+		var sql strings.Builder
+		args, err := c.cond.appendSQL(NewQuery(), &sql, nil)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", c.name, err)
+		}
+		if sql.String() != c.expectedExpr {
+			t.Errorf("%s:\nexpected: %q\ngot: %q", c.name, c.expectedExpr, sql.String())
+		}
+		if !reflect.DeepEqual(args, c.expectedArgs) {
+			t.Errorf("%s: expected args: %v, got: %v", c.name, c.expectedArgs, args)
+		}
+	}
+}
+
+func TestQueryBuilder_Build_WhereCond(t *testing.T) {
+	qb := NewQuery().
+		WithDialect(Postgres).
+		Select("id").
+		From("users").
+		WhereCond(Or(Eq("a", 1), Eq("b", 2))).
+		Where("c = ?", []any{3})
+
+	sql, args, err := qb.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedExpr := "SELECT id FROM users WHERE (a = $1 OR b = $2) AND c = $3"
+	expectedArgs := []any{1, 2, 3}
+
+	if sql != expectedExpr {
+		t.Errorf("expected SQL:\n%s\ngot:\n%s", expectedExpr, sql)
+	}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("expected args: %v, got: %v", expectedArgs, args)
+	}
+}
+
+func TestQueryBuilder_Build_WhereCond_Alone(t *testing.T) {
+	qb := NewQuery().
+		Select("id").
+		From("users").
+		WhereCond(Or(Eq("a", 1), And(Eq("b", 2), Lt("c", 3))))
+
+	sql, args, err := qb.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedExpr := "SELECT id FROM users WHERE a = ? OR (b = ? AND c < ?)"
+	expectedArgs := []any{1, 2, 3}
+
+	if sql != expectedExpr {
+		t.Errorf("expected SQL:\n%s\ngot:\n%s", expectedExpr, sql)
+	}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("expected args: %v, got: %v", expectedArgs, args)
+	}
+}
+
+func TestQueryBuilder_Build_CondAsArg(t *testing.T) {
+	qb := NewQuery().
+		Select("id").
+		From("tickets").
+		Where("tenant_id = ? AND ?", []any{7, Or(Eq("status", "open"), Gt("priority", 2))})
+
+	sql, args, err := qb.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedExpr := "SELECT id FROM tickets WHERE tenant_id = ? AND (status = ? OR priority > ?)"
+	expectedArgs := []any{7, "open", 2}
+
+	if sql != expectedExpr {
+		t.Errorf("expected SQL:\n%s\ngot:\n%s", expectedExpr, sql)
+	}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("expected args: %v, got: %v", expectedArgs, args)
+	}
+}
+
+func TestQueryBuilder_Build_HavingAndJoinCond(t *testing.T) {
+	qb := NewQuery().
+		WithDialect(Postgres).
+		QuoteIdentifiers().
+		Select("users.id").
+		From("users").
+		JoinCond("orders", And(Raw("orders.user_id = users.id"), Eq("orders.status", "paid"))).
+		LeftJoinCond("notes", Raw("notes.user_id = users.id")).
+		Where("users.active = ?", []any{true}).
+		GroupBy("users.id").
+		HavingCond(Or(Gt("COUNT(orders.id)", 5), Between("SUM(orders.total)", 10, 100)))
+
+	sql, args, err := qb.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedExpr := `SELECT "users"."id" FROM "users" INNER JOIN "orders" ON (orders.user_id = users.id) AND "orders"."status" = $1 LEFT JOIN "notes" ON notes.user_id = users.id WHERE users.active = $2 GROUP BY "users"."id" HAVING COUNT(orders.id) > $3 OR SUM(orders.total) BETWEEN $4 AND $5`
+	expectedArgs := []any{"paid", true, 5, 10, 100}
+
+	if sql != expectedExpr {
+		t.Errorf("expected SQL:\n%s\ngot:\n%s", expectedExpr, sql)
+	}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("expected args: %v, got: %v", expectedArgs, args)
+	}
+}
+
+func TestQueryBuilder_Build_CondArgMismatch(t *testing.T) {
+	qb := NewQuery().
+		Select("id").
+		From("users").
+		Where("a = ? AND ?", []any{Eq("b", 1)})
+
+	if _, _, err := qb.Build(); err == nil {
+		t.Fatal("expected error for missing placeholder arg")
+	}
+}
+
+func TestQueryBuilder_Build_NilCond(t *testing.T) {
+	cases := []struct {
+		name string
+		qb   *QueryBuilder
+	}{
+		{"where", NewQuery().Select("id").From("users").WhereCond(nil)},
+		{"not", NewQuery().Select("id").From("users").WhereCond(Not(nil))},
+		{"having", NewQuery().Select("id").From("users").GroupBy("id").HavingCond(nil)},
+		{"join", NewQuery().Select("id").From("users").JoinCond("orders", nil)},
+		{"left join", NewQuery().Select("id").From("users").LeftJoinCond("orders", nil)},
+	}
+
+	for _, c := range cases {
+		if _, _, err := c.qb.Build(); err == nil {
+			t.Errorf("%s: expected error for nil condition", c.name)
+		}
+	}
+}
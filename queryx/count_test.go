@@ -0,0 +1,145 @@
+package queryx
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCountTotal_Wrapping(t *testing.T) {
+	cases := []struct {
+		name         string
+		qb           *QueryBuilder
+		expectedExpr string
+		expectedArgs []any
+	}{
+		{
+			"distinct",
+			NewQuery().
+				Select("DISTINCT author_id").
+				From("posts").
+				Where("published = ?", []any{true}).
+				OrderBy("author_id").
+				Limit(10).
+				CountTotal(),
+			"SELECT COUNT(*) FROM (SELECT DISTINCT author_id FROM posts WHERE published = ?) AS subquery",
+			[]any{true},
+		},
+		{
+			"having on a select alias",
+			NewQuery().
+				WithDialect(MySQL).
+				Select("author_id", "COUNT(*) AS posts").
+				From("posts").
+				GroupBy("author_id").
+				Having("posts > ?", []any{10}).
+				OrderBy("posts DESC").
+				CountTotal(),
+			"SELECT COUNT(*) FROM (SELECT author_id, COUNT(*) AS posts FROM posts GROUP BY author_id HAVING posts > ?) AS subquery",
+			[]any{10},
+		},
+		{
+			"group by without select",
+			NewQuery().
+				From("orders").
+				GroupBy("user_id").
+				CountTotal(),
+			"SELECT COUNT(*) FROM (SELECT 1 FROM orders GROUP BY user_id) AS subquery",
+			nil,
+		},
+		{
+			"group by with clashing columns",
+			NewQuery().
+				WithDialect(SQLServer).
+				Select("users.id", "orders.id", "COUNT(*)").
+				From("users").
+				Join("orders", "users.id = orders.user_id", nil).
+				GroupBy("users.id", "orders.id").
+				Having("COUNT(*) > ?", []any{1}).
+				CountTotal(),
+			"SELECT COUNT(*) FROM (SELECT 1 FROM users INNER JOIN orders ON users.id = orders.user_id GROUP BY users.id, orders.id HAVING COUNT(*) > @p1) AS subquery",
+			[]any{1},
+		},
+		{
+			"cte with distinct",
+			NewQuery().
+				WithDialect(Postgres).
+				With("recent", NewQuery().Select("*").From("posts").Where("created_at > ?", []any{"2024-01-01"})).
+				Select("DISTINCT ON (author_id) author_id", "title").
+				From("recent").
+				CountTotal(),
+			"WITH recent AS (SELECT * FROM posts WHERE created_at > $1) SELECT COUNT(*) FROM (SELECT DISTINCT ON (author_id) author_id, title FROM recent) AS subquery",
+			[]any{"2024-01-01"},
+		},
+		{
+			"count distinct",
+			NewQuery().
+				Select("id", "author_id").
+				From("posts").
+				Where("published = ?", []any{true}).
+				CountDistinct("author_id"),
+			"SELECT COUNT(DISTINCT author_id) FROM posts WHERE published = ?",
+			[]any{true},
+		},
+		{
+			"count distinct of a grouped query",
+			NewQuery().
+				WithDialect(Postgres).
+				QuoteIdentifiers().
+				Select("author_id", "category").
+				From("posts").
+				GroupBy("author_id", "category").
+				CountDistinct("author_id"),
+			`SELECT COUNT(DISTINCT "author_id") FROM (SELECT "author_id", "category" FROM "posts" GROUP BY "author_id", "category") AS subquery`,
+			nil,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			expr, args, err := c.qb.Build()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if expr != c.expectedExpr {
+				t.Errorf("expected SQL:\n%s\ngot:\n%s", c.expectedExpr, expr)
+			}
+			if !reflect.DeepEqual(args, c.expectedArgs) {
+				t.Errorf("expected args %v, got %v", c.expectedArgs, args)
+			}
+		})
+	}
+}
+
+func TestExists(t *testing.T) {
+	qb := func(d Dialect) *QueryBuilder {
+		return NewQuery().
+			WithDialect(d).
+			Select("id").
+			From("users").
+			Where("email = ?", []any{"a@b.c"}).
+			OrderBy("id").
+			Limit(1)
+	}
+
+	cases := []struct {
+		dialect      Dialect
+		expectedExpr string
+	}{
+		{Postgres, "SELECT EXISTS (SELECT id FROM users WHERE email = $1)"},
+		{SQLServer, "SELECT CASE WHEN EXISTS (SELECT id FROM users WHERE email = @p1) THEN 1 ELSE 0 END"},
+		{Oracle, "SELECT CASE WHEN EXISTS (SELECT id FROM users WHERE email = :1) THEN 1 ELSE 0 END FROM DUAL"},
+	}
+
+	for _, c := range cases {
+		expr, args, err := qb(c.dialect).Exists().Build()
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", c.dialect.Name(), err)
+		}
+		if expr != c.expectedExpr {
+			t.Errorf("%s: expected SQL:\n%s\ngot:\n%s", c.dialect.Name(), c.expectedExpr, expr)
+		}
+		if !reflect.DeepEqual(args, []any{"a@b.c"}) {
+			t.Errorf("%s: unexpected args %v", c.dialect.Name(), args)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package queryx
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCTE_Select(t *testing.T) {
+	recent := NewQuery().
+		Select("user_id", "SUM(total) AS total").
+		From("orders").
+		Where("created_at > ?", []any{"2024-01-01"}).
+		GroupBy("user_id")
+
+	qb := NewQuery().
+		WithDialect(Postgres).
+		With("recent", recent).
+		Select("users.id", "recent.total").
+		From("users").
+		Join("recent", "recent.user_id = users.id", nil).
+		Where("recent.total > ?", []any{100})
+
+	sql, args, err := qb.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedExpr := "WITH recent AS (SELECT user_id, SUM(total) AS total FROM orders WHERE created_at > $1 GROUP BY user_id) SELECT users.id, recent.total FROM users INNER JOIN recent ON recent.user_id = users.id WHERE recent.total > $2"
+	expectedArgs := []any{"2024-01-01", 100}
+
+	if sql != expectedExpr {
+		t.Errorf("expected SQL:\n%s\ngot:\n%s", expectedExpr, sql)
+	}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("expected args: %v, got: %v", expectedArgs, args)
+	}
+}
+
+func TestCTE_Recursive(t *testing.T) {
+	anchor := NewQuery().
+		Select("id", "parent_id", "name").
+		From("categories").
+		Where("id = ?", []any{1})
+	recursive := NewQuery().
+		Select("c.id", "c.parent_id", "c.name").
+		From("categories c").
+		Join("tree t", "c.parent_id = t.id", nil)
+
+	qb := NewQuery().
+		WithDialect(Postgres).
+		WithRecursive("tree", []string{"id", "parent_id", "name"}, anchor, recursive).
+		Select("id", "name").
+		From("tree").
+		Where("name <> ?", []any{"hidden"})
+
+	sql, args, err := qb.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedExpr := "WITH RECURSIVE tree (id, parent_id, name) AS (SELECT id, parent_id, name FROM categories WHERE id = $1 UNION ALL SELECT c.id, c.parent_id, c.name FROM categories c INNER JOIN tree t ON c.parent_id = t.id) SELECT id, name FROM tree WHERE name <> $2"
+	expectedArgs := []any{1, "hidden"}
+
+	if sql != expectedExpr {
+		t.Errorf("expected SQL:\n%s\ngot:\n%s", expectedExpr, sql)
+	}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("expected args: %v, got: %v", expectedArgs, args)
+	}
+}
+
+func TestCTE_Recursive_SQLServer(t *testing.T) {
+	anchor := NewQuery().Select("id").From("categories").Where("parent_id IS NULL", nil)
+	recursive := NewQuery().Select("c.id").From("categories c").Join("tree t", "c.parent_id = t.id", nil)
+
+	qb := NewQuery().
+		WithDialect(SQLServer).
+		WithRecursive("tree", []string{"id"}, anchor, recursive).
+		Select("id").
+		From("tree")
+
+	sql, _, err := qb.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedExpr := "WITH tree (id) AS (SELECT id FROM categories WHERE parent_id IS NULL UNION ALL SELECT c.id FROM categories c INNER JOIN tree t ON c.parent_id = t.id) SELECT id FROM tree"
+	if sql != expectedExpr {
+		t.Errorf("expected SQL:\n%s\ngot:\n%s", expectedExpr, sql)
+	}
+}
+
+func TestCTE_WriteStatements(t *testing.T) {
+	stale := NewQuery().Select("id").From("sessions").Where("expires_at < ?", []any{"2024-01-01"})
+
+	cases := []struct {
+		name         string
+		qb           *QueryBuilder
+		expectedExpr string
+		expectedArgs []any
+	}{
+		{
+			"update",
+			NewQuery().
+				WithDialect(Postgres).
+				With("stale", stale).
+				Update("sessions", []string{"active"}).
+				Values(false).
+				Where("id IN (SELECT id FROM stale)", nil),
+			"WITH stale AS (SELECT id FROM sessions WHERE expires_at < $1) UPDATE sessions SET active = $2 WHERE id IN (SELECT id FROM stale)",
+			[]any{"2024-01-01", false},
+		},
+		{
+			"delete",
+			NewQuery().
+				WithDialect(Postgres).
+				With("stale", stale).
+				Delete("sessions").
+				Where("id IN (SELECT id FROM stale)", nil),
+			"WITH stale AS (SELECT id FROM sessions WHERE expires_at < $1) DELETE FROM sessions WHERE id IN (SELECT id FROM stale)",
+			[]any{"2024-01-01"},
+		},
+		{
+			"insert",
+			NewQuery().
+				WithDialect(Postgres).
+				With("stale", stale).
+				Insert("audit", []string{"note"}).
+				Values("cleanup"),
+			"WITH stale AS (SELECT id FROM sessions WHERE expires_at < $1) INSERT INTO audit (note) VALUES ($2)",
+			[]any{"2024-01-01", "cleanup"},
+		},
+	}
+
+	for _, c := range cases {
+		sql, args, err := c.qb.Build()
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", c.name, err)
+		}
+		if sql != c.expectedExpr {
+			t.Errorf("%s: expected SQL:\n%s\ngot:\n%s", c.name, c.expectedExpr, sql)
+		}
+		if !reflect.DeepEqual(args, c.expectedArgs) {
+			t.Errorf("%s: expected args: %v, got: %v", c.name, c.expectedArgs, args)
+		}
+	}
+}
+
+func TestCTE_CountTotal(t *testing.T) {
+	qb := NewQuery().
+		With("active", NewQuery().Select("id").From("users").Where("active = ?", []any{true})).
+		Select("id").
+		From("active").
+		Limit(10)
+
+	sql, args, err := qb.CountTotal().Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedExpr := "WITH active AS (SELECT id FROM users WHERE active = ?) SELECT COUNT(*) FROM active"
+	expectedArgs := []any{true}
+
+	if sql != expectedExpr {
+		t.Errorf("expected SQL:\n%s\ngot:\n%s", expectedExpr, sql)
+	}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("expected args: %v, got: %v", expectedArgs, args)
+	}
+}
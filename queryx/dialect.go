@@ -0,0 +1,348 @@
+package queryx
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Dialect describes how a QueryBuilder renders SQL for a specific database.
+//
+// Conditions are always written with "?" placeholders. Build renders the
+// whole statement first and then rewrites each placeholder with the
+// dialect's bind parameter, so numbering follows argument order across
+// joins, where, having, limit and offset.
+type Dialect interface {
+	// Name returns a short identifier for the dialect, e.g. "postgres".
+	Name() string
+	// Placeholder returns the bind parameter for the n-th argument, starting at 1.
+	Placeholder(n int) string
+	// Pagination reports how Limit and Offset are rendered.
+	Pagination() PaginationStyle
+	// QuoteIdent quotes a single identifier part, such as a table or column
+	// name, escaping any quote characters it contains.
+	QuoteIdent(name string) string
+	// Supports reports whether the dialect accepts an optional piece of syntax.
+	Supports(f Feature) bool
+}
+
+// Feature identifies optional SQL syntax that not every dialect accepts.
+type Feature int
+
+const (
+	// FeatureRecursiveKeyword marks dialects that spell recursive common
+	// table expressions as WITH RECURSIVE.
+	FeatureRecursiveKeyword Feature = iota
+	// FeatureCompoundParens marks dialects that accept parenthesized
+	// branches in UNION, INTERSECT and EXCEPT.
+	FeatureCompoundParens
+	// FeatureFullJoin marks dialects that support FULL OUTER JOIN.
+	FeatureFullJoin
+	// FeatureLateralJoin marks dialects that support LATERAL joins.
+	FeatureLateralJoin
+	// FeatureJoinUsing marks dialects that support JOIN ... USING (...).
+	FeatureJoinUsing
+	// FeatureOnConflict marks dialects that support INSERT ... ON CONFLICT.
+	FeatureOnConflict
+	// FeatureOnDuplicateKey marks dialects that support INSERT ... ON
+	// DUPLICATE KEY UPDATE and refer to the inserted row with VALUES(col).
+	FeatureOnDuplicateKey
+	// FeatureReturning marks dialects that support a trailing RETURNING
+	// clause on insert, update and delete.
+	FeatureReturning
+	// FeatureOutput marks dialects that return affected rows through an
+	// OUTPUT clause.
+	FeatureOutput
+	// FeatureUpdateFrom marks dialects that take the other tables of a
+	// multi-table update in a FROM clause.
+	FeatureUpdateFrom
+	// FeatureUpdateJoin marks dialects that join the other tables of a
+	// multi-table update before SET.
+	FeatureUpdateJoin
+	// FeatureDeleteUsing marks dialects that take the other tables of a
+	// multi-table delete in a USING clause.
+	FeatureDeleteUsing
+	// FeatureDeleteJoin marks dialects that support "DELETE t FROM t JOIN ...".
+	FeatureDeleteJoin
+	// FeatureReleaseSavepoint marks dialects that support RELEASE SAVEPOINT.
+	FeatureReleaseSavepoint
+	// FeatureSaveTransaction marks dialects that create savepoints with
+	// SAVE TRANSACTION and roll back to them with ROLLBACK TRANSACTION.
+	FeatureSaveTransaction
+	// FeatureRowValues marks dialects that compare row values, as in
+	// "(a, b) > (?, ?)".
+	FeatureRowValues
+	// FeatureNullsOrdering marks dialects that support NULLS FIRST and
+	// NULLS LAST in ORDER BY.
+	FeatureNullsOrdering
+	// FeatureNullsSortLow marks dialects that sort NULLs before other values
+	// in ascending order.
+	FeatureNullsSortLow
+	// FeatureSelectExists marks dialects that select a boolean expression
+	// such as "SELECT EXISTS (...)" directly.
+	FeatureSelectExists
+	// FeatureFromDual marks dialects whose SELECT requires a FROM clause,
+	// filled with the DUAL table when nothing is read.
+	FeatureFromDual
+	// FeatureGroupingSets marks dialects that support ROLLUP (...), CUBE (...)
+	// and GROUPING SETS in GROUP BY.
+	FeatureGroupingSets
+	// FeatureWithRollup marks dialects that only support rollups written as
+	// "GROUP BY a, b WITH ROLLUP".
+	FeatureWithRollup
+	// FeatureAggregateFilter marks dialects that support FILTER (WHERE ...)
+	// on aggregates.
+	FeatureAggregateFilter
+	// FeatureArrayAgg marks dialects that support ARRAY_AGG.
+	FeatureArrayAgg
+	// FeatureGroupConcat marks dialects that concatenate strings with
+	// GROUP_CONCAT(... SEPARATOR ...) rather than STRING_AGG.
+	FeatureGroupConcat
+	// FeatureListAgg marks dialects that concatenate strings with LISTAGG
+	// rather than STRING_AGG.
+	FeatureListAgg
+	// FeatureWithinGroup marks dialects that order the values of a string
+	// aggregate with WITHIN GROUP (ORDER BY ...).
+	FeatureWithinGroup
+	// FeatureTableAliasAs marks dialects that accept AS before a table alias,
+	// as in "FROM (SELECT ...) AS s". Oracle only accepts "FROM (SELECT ...) s".
+	FeatureTableAliasAs
+	// FeatureHavingAliases marks dialects that resolve select aliases in
+	// HAVING, as in "SELECT COUNT(*) AS n ... HAVING n > ?".
+	FeatureHavingAliases
+)
+
+// PaginationStyle selects the syntax used for Limit and Offset.
+type PaginationStyle int
+
+const (
+	// LimitOffset renders "LIMIT ? OFFSET ?".
+	LimitOffset PaginationStyle = iota
+	// OffsetFetch renders "OFFSET ? ROWS FETCH NEXT ? ROWS ONLY", or
+	// "FETCH FIRST ? ROWS ONLY" when only a limit is set.
+	OffsetFetch
+	// TopOffsetFetch renders "SELECT TOP (?)" when only a limit is set and
+	// "OFFSET ? ROWS FETCH NEXT ? ROWS ONLY" otherwise.
+	TopOffsetFetch
+)
+
+var (
+	// Default renders "?" placeholders and is used when no dialect is set.
+	Default Dialect = defaultDialect{}
+	// MySQL renders "?" placeholders.
+	MySQL Dialect = mysqlDialect{}
+	// Postgres renders "$1", "$2", ... placeholders.
+	Postgres Dialect = postgresDialect{}
+	// SQLite renders "?" placeholders.
+	SQLite Dialect = sqliteDialect{}
+	// SQLServer renders "@p1", "@p2", ... placeholders and paginates with
+	// TOP or OFFSET ... FETCH.
+	SQLServer Dialect = sqlserverDialect{}
+	// Oracle renders ":1", ":2", ... placeholders and paginates with
+	// OFFSET ... FETCH.
+	Oracle Dialect = oracleDialect{}
+)
+
+type defaultDialect struct{}
+
+func (defaultDialect) Name() string                  { return "default" }
+func (defaultDialect) Placeholder(int) string        { return "?" }
+func (defaultDialect) Pagination() PaginationStyle   { return LimitOffset }
+func (defaultDialect) QuoteIdent(name string) string { return doubleQuote(name) }
+
+func (defaultDialect) Supports(f Feature) bool {
+	switch f {
+	case FeatureRecursiveKeyword,
+		FeatureCompoundParens,
+		FeatureFullJoin,
+		FeatureLateralJoin,
+		FeatureJoinUsing,
+		FeatureOnConflict,
+		FeatureReturning,
+		FeatureUpdateFrom,
+		FeatureDeleteUsing,
+		FeatureReleaseSavepoint,
+		FeatureRowValues,
+		FeatureNullsOrdering,
+		FeatureSelectExists,
+		FeatureGroupingSets,
+		FeatureAggregateFilter,
+		FeatureArrayAgg,
+		FeatureTableAliasAs:
+		return true
+	}
+	return false
+}
+
+type mysqlDialect struct{}
+
+func (mysqlDialect) Name() string                  { return "mysql" }
+func (mysqlDialect) Placeholder(int) string        { return "?" }
+func (mysqlDialect) Pagination() PaginationStyle   { return LimitOffset }
+func (mysqlDialect) QuoteIdent(name string) string { return backtickQuote(name) }
+
+func (mysqlDialect) Supports(f Feature) bool {
+	switch f {
+	case FeatureRecursiveKeyword,
+		FeatureCompoundParens,
+		FeatureLateralJoin,
+		FeatureJoinUsing,
+		FeatureOnDuplicateKey,
+		FeatureUpdateJoin,
+		FeatureDeleteJoin,
+		FeatureReleaseSavepoint,
+		FeatureRowValues,
+		FeatureNullsSortLow,
+		FeatureSelectExists,
+		FeatureWithRollup,
+		FeatureGroupConcat,
+		FeatureTableAliasAs,
+		FeatureHavingAliases:
+		return true
+	}
+	return false
+}
+
+type postgresDialect struct{}
+
+func (postgresDialect) Name() string                  { return "postgres" }
+func (postgresDialect) Placeholder(n int) string      { return "$" + strconv.Itoa(n) }
+func (postgresDialect) Pagination() PaginationStyle   { return LimitOffset }
+func (postgresDialect) QuoteIdent(name string) string { return doubleQuote(name) }
+
+func (postgresDialect) Supports(f Feature) bool {
+	switch f {
+	case FeatureRecursiveKeyword,
+		FeatureCompoundParens,
+		FeatureFullJoin,
+		FeatureLateralJoin,
+		FeatureJoinUsing,
+		FeatureOnConflict,
+		FeatureReturning,
+		FeatureUpdateFrom,
+		FeatureDeleteUsing,
+		FeatureReleaseSavepoint,
+		FeatureRowValues,
+		FeatureNullsOrdering,
+		FeatureSelectExists,
+		FeatureGroupingSets,
+		FeatureAggregateFilter,
+		FeatureArrayAgg,
+		FeatureTableAliasAs:
+		return true
+	}
+	return false
+}
+
+type sqliteDialect struct{}
+
+func (sqliteDialect) Name() string                  { return "sqlite" }
+func (sqliteDialect) Placeholder(int) string        { return "?" }
+func (sqliteDialect) Pagination() PaginationStyle   { return LimitOffset }
+func (sqliteDialect) QuoteIdent(name string) string { return doubleQuote(name) }
+
+func (sqliteDialect) Supports(f Feature) bool {
+	switch f {
+	case FeatureRecursiveKeyword,
+		FeatureFullJoin,
+		FeatureJoinUsing,
+		FeatureOnConflict,
+		FeatureReturning,
+		FeatureUpdateFrom,
+		FeatureReleaseSavepoint,
+		FeatureRowValues,
+		FeatureNullsOrdering,
+		FeatureNullsSortLow,
+		FeatureSelectExists,
+		FeatureAggregateFilter,
+		FeatureTableAliasAs,
+		FeatureHavingAliases:
+		return true
+	}
+	return false
+}
+
+type sqlserverDialect struct{}
+
+func (sqlserverDialect) Name() string                  { return "sqlserver" }
+func (sqlserverDialect) Placeholder(n int) string      { return "@p" + strconv.Itoa(n) }
+func (sqlserverDialect) Pagination() PaginationStyle   { return TopOffsetFetch }
+func (sqlserverDialect) QuoteIdent(name string) string { return bracketQuote(name) }
+
+func (sqlserverDialect) Supports(f Feature) bool {
+	switch f {
+	case FeatureCompoundParens,
+		FeatureFullJoin,
+		FeatureOutput,
+		FeatureUpdateFrom,
+		FeatureDeleteJoin,
+		FeatureSaveTransaction,
+		FeatureNullsSortLow,
+		FeatureGroupingSets,
+		FeatureWithinGroup,
+		FeatureTableAliasAs:
+		return true
+	}
+	return false
+}
+
+type oracleDialect struct{}
+
+func (oracleDialect) Name() string                  { return "oracle" }
+func (oracleDialect) Placeholder(n int) string      { return ":" + strconv.Itoa(n) }
+func (oracleDialect) Pagination() PaginationStyle   { return OffsetFetch }
+func (oracleDialect) QuoteIdent(name string) string { return doubleQuote(name) }
+
+func (oracleDialect) Supports(f Feature) bool {
+	switch f {
+	case FeatureCompoundParens,
+		FeatureFullJoin,
+		FeatureLateralJoin,
+		FeatureJoinUsing,
+		FeatureNullsOrdering,
+		FeatureFromDual,
+		FeatureGroupingSets,
+		FeatureListAgg,
+		FeatureWithinGroup:
+		return true
+	}
+	return false
+}
+
+func doubleQuote(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+func backtickQuote(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+func bracketQuote(name string) string {
+	return "[" + strings.ReplaceAll(name, "]", "]]") + "]"
+}
+
+// bindPlaceholders replaces every "?" that is not inside a quoted string or
+// identifier with the dialect placeholder for its position.
+func bindPlaceholders(d Dialect, query string) string {
+	var b strings.Builder
+	b.Grow(len(query))
+
+	n := 0
+	var quote byte
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '?':
+			n++
+			b.WriteString(d.Placeholder(n))
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
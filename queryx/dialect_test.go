@@ -0,0 +1,260 @@
+package queryx
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDialect_Postgres_Select(t *testing.T) {
+	qb := NewQuery().
+		WithDialect(Postgres).
+		Select("users.id", "users.name").
+		From("users").
+		Join("orders", "users.id = orders.user_id AND orders.status = ?", []any{"paid"}).
+		Where("users.age > ?", []any{18}).
+		Where("users.active = ?", []any{true}).
+		GroupBy("users.id").
+		Having("COUNT(orders.id) > ?", []any{2}).
+		Limit(10).
+		Offset(20)
+
+	sql, args, err := qb.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedExpr := "SELECT users.id, users.name FROM users INNER JOIN orders ON users.id = orders.user_id AND orders.status = $1 WHERE users.age > $2 AND users.active = $3 GROUP BY users.id HAVING COUNT(orders.id) > $4 LIMIT $5 OFFSET $6"
+	expectedArgs := []any{"paid", 18, true, 2, 10, 20}
+
+	if sql != expectedExpr {
+		t.Errorf("expected SQL:\n%s\ngot:\n%s", expectedExpr, sql)
+	}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("expected args: %v, got: %v", expectedArgs, args)
+	}
+}
+
+func TestDialect_Postgres_Insert(t *testing.T) {
+	qb := NewQuery().
+		WithDialect(Postgres).
+		Insert("users", []string{"name", "email"}).
+		MultiValues([][]any{
+			{"John", "john@example.com"},
+			{"Jane", "jane@example.com"},
+		})
+
+	sql, _, err := qb.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedExpr := "INSERT INTO users (name, email) VALUES ($1, $2), ($3, $4)"
+	if sql != expectedExpr {
+		t.Errorf("expected SQL:\n%s\ngot:\n%s", expectedExpr, sql)
+	}
+}
+
+func TestDialect_Postgres_Update(t *testing.T) {
+	qb := NewQuery().
+		WithDialect(Postgres).
+		Update("users", []string{"name", "status"}).
+		Values("John Doe", "inactive").
+		Where("id = ?", []any{123})
+
+	sql, args, err := qb.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedExpr := "UPDATE users SET name = $1, status = $2 WHERE id = $3"
+	expectedArgs := []any{"John Doe", "inactive", 123}
+
+	if sql != expectedExpr {
+		t.Errorf("expected SQL:\n%s\ngot:\n%s", expectedExpr, sql)
+	}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("expected args: %v, got: %v", expectedArgs, args)
+	}
+}
+
+func TestDialect_Postgres_CountTotal(t *testing.T) {
+	qb := NewQuery().
+		WithDialect(Postgres).
+		Select("id").
+		From("users").
+		Where("age > ?", []any{18}).
+		Limit(10)
+
+	sql, _, err := qb.CountTotal().Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedExpr := "SELECT COUNT(*) FROM users WHERE age > $1"
+	if sql != expectedExpr {
+		t.Errorf("expected SQL:\n%s\ngot:\n%s", expectedExpr, sql)
+	}
+}
+
+func TestDialect_QuestionMarkDialects(t *testing.T) {
+	for _, d := range []Dialect{Default, MySQL, SQLite} {
+		sql, _, err := NewQuery().
+			WithDialect(d).
+			Select("id").
+			From("users").
+			Where("id = ?", []any{1}).
+			Build()
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", d.Name(), err)
+		}
+
+		expectedExpr := "SELECT id FROM users WHERE id = ?"
+		if sql != expectedExpr {
+			t.Errorf("%s: expected SQL:\n%s\ngot:\n%s", d.Name(), expectedExpr, sql)
+		}
+	}
+}
+
+func TestBindPlaceholders_SkipsQuoted(t *testing.T) {
+	sql := bindPlaceholders(Postgres, `SELECT '?', "a?b" FROM t WHERE x = ? AND y = 'it''s ?' AND z = ?`)
+
+	expected := `SELECT '?', "a?b" FROM t WHERE x = $1 AND y = 'it''s ?' AND z = $2`
+	if sql != expected {
+		t.Errorf("\nexpected: %q\ngot: %q", expected, sql)
+	}
+}
+
+func TestDialect_SQLServer_Top(t *testing.T) {
+	qb := NewQuery().
+		WithDialect(SQLServer).
+		Select("id", "name").
+		From("users").
+		Where("active = ?", []any{true}).
+		Limit(10)
+
+	sql, args, err := qb.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedExpr := "SELECT TOP (@p1) id, name FROM users WHERE active = @p2"
+	expectedArgs := []any{10, true}
+
+	if sql != expectedExpr {
+		t.Errorf("expected SQL:\n%s\ngot:\n%s", expectedExpr, sql)
+	}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("expected args: %v, got: %v", expectedArgs, args)
+	}
+}
+
+func TestDialect_SQLServer_DistinctTop(t *testing.T) {
+	qb := NewQuery().
+		WithDialect(SQLServer).
+		QuoteIdentifiers().
+		Select("DISTINCT city", "country").
+		From("users").
+		Limit(10)
+
+	sql, args, err := qb.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedExpr := "SELECT DISTINCT TOP (@p1) [city], [country] FROM [users]"
+	expectedArgs := []any{10}
+
+	if sql != expectedExpr {
+		t.Errorf("expected SQL:\n%s\ngot:\n%s", expectedExpr, sql)
+	}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("expected args: %v, got: %v", expectedArgs, args)
+	}
+}
+
+func TestDialect_SQLServer_OffsetFetch(t *testing.T) {
+	qb := NewQuery().
+		WithDialect(SQLServer).
+		Select("id", "name").
+		From("users").
+		Where("active = ?", []any{true}).
+		OrderBy("id").
+		Limit(10).
+		Offset(20)
+
+	sql, args, err := qb.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedExpr := "SELECT id, name FROM users WHERE active = @p1 ORDER BY id OFFSET @p2 ROWS FETCH NEXT @p3 ROWS ONLY"
+	expectedArgs := []any{true, 20, 10}
+
+	if sql != expectedExpr {
+		t.Errorf("expected SQL:\n%s\ngot:\n%s", expectedExpr, sql)
+	}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("expected args: %v, got: %v", expectedArgs, args)
+	}
+}
+
+func TestDialect_SQLServer_OffsetRequiresOrderBy(t *testing.T) {
+	qb := NewQuery().
+		WithDialect(SQLServer).
+		Select("id").
+		From("users").
+		Limit(10).
+		Offset(20)
+
+	if _, _, err := qb.Build(); err == nil {
+		t.Fatal("expected error for offset without order by")
+	}
+}
+
+func TestDialect_Oracle_FetchFirst(t *testing.T) {
+	qb := NewQuery().
+		WithDialect(Oracle).
+		Select("id").
+		From("users").
+		Where("id > ?", []any{5}).
+		Limit(10)
+
+	sql, args, err := qb.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedExpr := "SELECT id FROM users WHERE id > :1 FETCH FIRST :2 ROWS ONLY"
+	expectedArgs := []any{5, 10}
+
+	if sql != expectedExpr {
+		t.Errorf("expected SQL:\n%s\ngot:\n%s", expectedExpr, sql)
+	}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("expected args: %v, got: %v", expectedArgs, args)
+	}
+}
+
+func TestDialect_Oracle_OffsetFetch(t *testing.T) {
+	qb := NewQuery().
+		WithDialect(Oracle).
+		Select("id").
+		From("users").
+		OrderBy("id").
+		Offset(20)
+
+	sql, args, err := qb.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedExpr := "SELECT id FROM users ORDER BY id OFFSET :1 ROWS"
+	expectedArgs := []any{20}
+
+	if sql != expectedExpr {
+		t.Errorf("expected SQL:\n%s\ngot:\n%s", expectedExpr, sql)
+	}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("expected args: %v, got: %v", expectedArgs, args)
+	}
+}
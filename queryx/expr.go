@@ -0,0 +1,55 @@
+package queryx
+
+import (
+	"strings"
+)
+
+// Expr is a SQL expression rendered at Build time. Like a Cond, an Expr can
+// be passed as an argument of a raw fragment, where it replaces its "?"
+// placeholder. Every Cond is also an Expr.
+type Expr interface {
+	appendSQL(qb *QueryBuilder, b *strings.Builder, args []any) ([]any, error)
+}
+
+// Excluded refers to the value column would have had in the row proposed
+// for insertion, for use in upsert assignments and conditions. It renders
+// "EXCLUDED.column", or "VALUES(column)" on MySQL.
+func Excluded(column string) Expr {
+	return excludedRef{column: column}
+}
+
+type excludedRef struct {
+	column string
+}
+
+func (e excludedRef) appendSQL(qb *QueryBuilder, b *strings.Builder, args []any) ([]any, error) {
+	column := quoteColumn(qb, e.column)
+	if qb.getDialect().Supports(FeatureOnDuplicateKey) {
+		b.WriteString("VALUES(" + column + ")")
+	} else {
+		b.WriteString("EXCLUDED." + column)
+	}
+	return args, nil
+}
+
+// bindValue binds a value to a single placeholder. Unlike a plain argument
+// of a fragment, a slice is not expanded, so that it reaches the driver as
+// one value, as an array column expects.
+type bindValue struct {
+	value any
+}
+
+func (v bindValue) appendSQL(qb *QueryBuilder, b *strings.Builder, args []any) ([]any, error) {
+	b.WriteString("?")
+	return append(args, v.value), nil
+}
+
+// columnRef renders a column name, quoted when identifier quoting is on.
+type columnRef struct {
+	column string
+}
+
+func (c columnRef) appendSQL(qb *QueryBuilder, b *strings.Builder, args []any) ([]any, error) {
+	b.WriteString(quoteColumn(qb, c.column))
+	return args, nil
+}
@@ -0,0 +1,197 @@
+package queryx
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+)
+
+// fakeDB is an in-memory database/sql driver that records the statements it
+// receives and answers them with a handler.
+type fakeDB struct {
+	mu       sync.Mutex
+	calls    []fakeCall
+	openRows int
+	handle   func(query string, args []driver.Value) (*fakeResult, error)
+}
+
+type fakeCall struct {
+	query string
+	args  []driver.Value
+}
+
+type fakeResult struct {
+	columns      []string
+	rows         [][]driver.Value
+	rowsAffected int64
+}
+
+var (
+	fakeDBsMu sync.Mutex
+	fakeDBs   = map[string]*fakeDB{}
+)
+
+func init() {
+	sql.Register("queryxfake", fakeDriver{})
+}
+
+// newFakeDB opens a database backed by a fakeDB answering with handle. A
+// nil handle answers every statement with an empty result.
+func newFakeDB(t *testing.T, handle func(query string, args []driver.Value) (*fakeResult, error)) (*sql.DB, *fakeDB) {
+	t.Helper()
+
+	if handle == nil {
+		handle = func(string, []driver.Value) (*fakeResult, error) { return &fakeResult{}, nil }
+	}
+	fdb := &fakeDB{handle: handle}
+
+	fakeDBsMu.Lock()
+	dsn := fmt.Sprintf("%s/%d", t.Name(), len(fakeDBs))
+	fakeDBs[dsn] = fdb
+	fakeDBsMu.Unlock()
+
+	db, err := sql.Open("queryxfake", dsn)
+	if err != nil {
+		t.Fatalf("open fake db: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db, fdb
+}
+
+func (db *fakeDB) run(query string, args []driver.NamedValue) (*fakeResult, error) {
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		values[i] = arg.Value
+	}
+
+	db.mu.Lock()
+	db.calls = append(db.calls, fakeCall{query: query, args: values})
+	db.mu.Unlock()
+
+	return db.handle(query, values)
+}
+
+// queries returns the statements received so far.
+func (db *fakeDB) queries() []string {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	queries := make([]string, len(db.calls))
+	for i, c := range db.calls {
+		queries[i] = c.query
+	}
+	return queries
+}
+
+// open returns the number of result sets not closed yet.
+func (db *fakeDB) open() int {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	return db.openRows
+}
+
+func (db *fakeDB) lastCall() fakeCall {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	return db.calls[len(db.calls)-1]
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(dsn string) (driver.Conn, error) {
+	fakeDBsMu.Lock()
+	defer fakeDBsMu.Unlock()
+
+	db, ok := fakeDBs[dsn]
+	if !ok {
+		return nil, fmt.Errorf("unknown fake db %q", dsn)
+	}
+	return &fakeConn{db: db}, nil
+}
+
+type fakeConn struct {
+	db *fakeDB
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("fake driver does not prepare statements")
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *fakeConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if _, err := c.db.run("BEGIN", nil); err != nil {
+		return nil, err
+	}
+	return fakeTx{db: c.db}, nil
+}
+
+func (c *fakeConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	res, err := c.db.run(query, args)
+	if err != nil {
+		return nil, err
+	}
+	return driver.RowsAffected(res.rowsAffected), nil
+}
+
+func (c *fakeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	res, err := c.db.run(query, args)
+	if err != nil {
+		return nil, err
+	}
+	c.db.mu.Lock()
+	c.db.openRows++
+	c.db.mu.Unlock()
+	return &fakeRows{db: c.db, result: res}, nil
+}
+
+type fakeTx struct {
+	db *fakeDB
+}
+
+func (tx fakeTx) Commit() error {
+	_, err := tx.db.run("COMMIT", nil)
+	return err
+}
+
+func (tx fakeTx) Rollback() error {
+	_, err := tx.db.run("ROLLBACK", nil)
+	return err
+}
+
+type fakeRows struct {
+	db     *fakeDB
+	result *fakeResult
+	next   int
+}
+
+func (r *fakeRows) Columns() []string {
+	return r.result.columns
+}
+
+func (r *fakeRows) Close() error {
+	r.db.mu.Lock()
+	r.db.openRows--
+	r.db.mu.Unlock()
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.next >= len(r.result.rows) {
+		return io.EOF
+	}
+	copy(dest, r.result.rows[r.next])
+	r.next++
+	return nil
+}
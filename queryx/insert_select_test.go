@@ -0,0 +1,77 @@
+package queryx
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInsertSelect(t *testing.T) {
+	old := NewQuery().
+		Select("id", "user_id, total", "NOW()").
+		From("orders").
+		Where("created_at < ?", []any{"2023-01-01"})
+
+	qb := NewQuery().
+		WithDialect(Postgres).
+		Insert("orders_archive", []string{"id", "user_id", "total", "archived_at"}).
+		FromSelect(old).
+		OnConflict("id").
+		DoNothing()
+
+	sql, args, err := qb.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedExpr := "INSERT INTO orders_archive (id, user_id, total, archived_at) SELECT id, user_id, total, NOW() FROM orders WHERE created_at < $1 ON CONFLICT (id) DO NOTHING"
+	expectedArgs := []any{"2023-01-01"}
+
+	if sql != expectedExpr {
+		t.Errorf("expected SQL:\n%s\ngot:\n%s", expectedExpr, sql)
+	}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("expected args: %v, got: %v", expectedArgs, args)
+	}
+}
+
+func TestInsertSelect_UnknownColumnCount(t *testing.T) {
+	qb := NewQuery().
+		Insert("users_backup", []string{"id", "name"}).
+		FromSelect(NewQuery().Select("*").From("users"))
+
+	sql, _, err := qb.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedExpr := "INSERT INTO users_backup (id, name) SELECT * FROM users"
+	if sql != expectedExpr {
+		t.Errorf("expected SQL:\n%s\ngot:\n%s", expectedExpr, sql)
+	}
+}
+
+func TestInsertSelect_Errors(t *testing.T) {
+	cases := []struct {
+		name string
+		qb   *QueryBuilder
+	}{
+		{
+			"column count mismatch",
+			NewQuery().Insert("t", []string{"a", "b"}).FromSelect(NewQuery().Select("a", "COUNT(*)", "c").From("s")),
+		},
+		{
+			"values and select",
+			NewQuery().Insert("t", []string{"a"}).Values(1).FromSelect(NewQuery().Select("a").From("s")),
+		},
+		{
+			"invalid select",
+			NewQuery().Insert("t", []string{"a"}).FromSelect(NewQuery().Select("a")),
+		},
+	}
+
+	for _, c := range cases {
+		if _, _, err := c.qb.Build(); err == nil {
+			t.Errorf("%s: expected error", c.name)
+		}
+	}
+}
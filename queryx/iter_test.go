@@ -0,0 +1,75 @@
+package queryx
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestIter(t *testing.T) {
+	db, fdb := newFakeDB(t, rowsOf(
+		[]string{"id", "name"},
+		[]driver.Value{int64(1), "Core"},
+		[]driver.Value{int64(2), "Ops"},
+		[]driver.Value{int64(3), "Data"},
+	))
+	qb := NewQuery().Select("id", "name").From("teams")
+
+	seq := Iter[scanTeam](context.Background(), db, qb)
+	if len(fdb.queries()) != 0 {
+		t.Fatal("expected the query to run only when iteration starts")
+	}
+
+	var teams []scanTeam
+	for team, err := range seq {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		teams = append(teams, team)
+	}
+
+	expected := []scanTeam{{1, "Core"}, {2, "Ops"}, {3, "Data"}}
+	if !reflect.DeepEqual(teams, expected) {
+		t.Errorf("expected %+v, got %+v", expected, teams)
+	}
+	if n := fdb.open(); n != 0 {
+		t.Errorf("expected rows to be closed, %d still open", n)
+	}
+}
+
+func TestIter_Break(t *testing.T) {
+	db, fdb := newFakeDB(t, rowsOf([]string{"id"}, []driver.Value{int64(1)}, []driver.Value{int64(2)}))
+
+	for id, err := range Iter[int](context.Background(), db, NewQuery().Select("id").From("teams")) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if id == 1 {
+			break
+		}
+	}
+
+	if n := fdb.open(); n != 0 {
+		t.Errorf("expected rows to be closed on break, %d still open", n)
+	}
+}
+
+func TestIter_Error(t *testing.T) {
+	queryErr := errors.New("boom")
+	db, _ := newFakeDB(t, func(string, []driver.Value) (*fakeResult, error) {
+		return nil, queryErr
+	})
+
+	calls := 0
+	for _, err := range Iter[int](context.Background(), db, NewQuery().Select("id").From("teams")) {
+		calls++
+		if !errors.Is(err, queryErr) {
+			t.Errorf("expected %v, got %v", queryErr, err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("expected a single error, got %d iterations", calls)
+	}
+}
@@ -0,0 +1,103 @@
+package queryx
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestJoin_Types(t *testing.T) {
+	qb := NewQuery().
+		WithDialect(Postgres).
+		Select("l.id", "r.id").
+		From("ledger l").
+		FullJoin("remote r", "r.ref = l.ref AND r.day = ?", []any{"2024-01-01"}).
+		RightJoin("accounts a", "a.id = l.account_id", nil).
+		CrossJoin("currencies c").
+		Where("l.amount <> ?", []any{0})
+
+	sql, args, err := qb.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedExpr := "SELECT l.id, r.id FROM ledger l FULL OUTER JOIN remote r ON r.ref = l.ref AND r.day = $1 RIGHT JOIN accounts a ON a.id = l.account_id CROSS JOIN currencies c WHERE l.amount <> $2"
+	expectedArgs := []any{"2024-01-01", 0}
+
+	if sql != expectedExpr {
+		t.Errorf("expected SQL:\n%s\ngot:\n%s", expectedExpr, sql)
+	}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("expected args: %v, got: %v", expectedArgs, args)
+	}
+}
+
+func TestJoin_Using(t *testing.T) {
+	qb := NewQuery().
+		WithDialect(MySQL).
+		QuoteIdentifiers().
+		Select("id").
+		From("orders").
+		JoinUsing("customers", "customer_id").
+		LeftJoinUsing("shipments", "order_id", "region")
+
+	sql, _, err := qb.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedExpr := "SELECT `id` FROM `orders` INNER JOIN `customers` USING (`customer_id`) LEFT JOIN `shipments` USING (`order_id`, `region`)"
+	if sql != expectedExpr {
+		t.Errorf("expected SQL:\n%s\ngot:\n%s", expectedExpr, sql)
+	}
+}
+
+func TestJoin_Lateral(t *testing.T) {
+	latest := NewQuery().
+		Select("total").
+		From("orders o").
+		Where("o.user_id = u.id AND o.status = ?", []any{"paid"}).
+		OrderBy("o.created_at DESC").
+		Limit(3)
+
+	qb := NewQuery().
+		WithDialect(Postgres).
+		Select("u.id", "lo.total").
+		From("users u").
+		LeftJoinLateral(latest, "lo", "", nil).
+		CrossJoinLateral(NewQuery().Select("COUNT(*) AS n").From("logins l").Where("l.user_id = u.id", nil), "ln")
+
+	sql, args, err := qb.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedExpr := "SELECT u.id, lo.total FROM users u LEFT JOIN LATERAL (SELECT total FROM orders o WHERE o.user_id = u.id AND o.status = $1 ORDER BY o.created_at DESC LIMIT $2) AS lo ON 1=1 CROSS JOIN LATERAL (SELECT COUNT(*) AS n FROM logins l WHERE l.user_id = u.id) AS ln"
+	expectedArgs := []any{"paid", 3}
+
+	if sql != expectedExpr {
+		t.Errorf("expected SQL:\n%s\ngot:\n%s", expectedExpr, sql)
+	}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("expected args: %v, got: %v", expectedArgs, args)
+	}
+}
+
+func TestJoin_UnsupportedByDialect(t *testing.T) {
+	sub := NewQuery().Select("id").From("b")
+
+	cases := []struct {
+		name string
+		qb   *QueryBuilder
+	}{
+		{"full join on mysql", NewQuery().WithDialect(MySQL).Select("id").From("a").FullJoin("b", "b.id = a.id", nil)},
+		{"lateral on sqlserver", NewQuery().WithDialect(SQLServer).Select("id").From("a").JoinLateral(sub, "s", "s.id = a.id", nil)},
+		{"lateral on sqlite", NewQuery().WithDialect(SQLite).Select("id").From("a").CrossJoinLateral(sub, "s")},
+		{"using on sqlserver", NewQuery().WithDialect(SQLServer).Select("id").From("a").JoinUsing("b", "id")},
+	}
+
+	for _, c := range cases {
+		if _, _, err := c.qb.Build(); err == nil {
+			t.Errorf("%s: expected error", c.name)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package queryx
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// SetMap adds one assignment per entry of values to an update, in sorted
+// column order so the generated SQL is stable. Each value is bound as Set
+// binds it, so a decoded JSON array reaches the driver as a single value.
+func (qb *QueryBuilder) SetMap(values map[string]any) *QueryBuilder {
+	if len(values) == 0 {
+		return qb.fail(errors.New("SetMap: no columns to update"))
+	}
+	for _, col := range slices.Sorted(maps.Keys(values)) {
+		qb.Set(col, values[col])
+	}
+	return qb
+}
+
+// InsertMap inserts a row made of the entries of values, in sorted column
+// order.
+func (qb *QueryBuilder) InsertMap(table string, values map[string]any) *QueryBuilder {
+	if len(values) == 0 {
+		return qb.fail(errors.New("InsertMap: no columns to insert"))
+	}
+
+	columns := slices.Sorted(maps.Keys(values))
+	row := make([]any, len(columns))
+	for i, col := range columns {
+		row[i] = values[col]
+	}
+	return qb.Insert(table, columns).Values(row...)
+}
+
+// InsertMaps inserts one row per map, in sorted column order. Every row must
+// have the same set of columns.
+func (qb *QueryBuilder) InsertMaps(table string, rows []map[string]any) *QueryBuilder {
+	if len(rows) == 0 {
+		return qb.fail(errors.New("InsertMaps: no rows to insert"))
+	}
+	if len(rows[0]) == 0 {
+		return qb.fail(errors.New("InsertMaps: no columns to insert"))
+	}
+
+	columns := slices.Sorted(maps.Keys(rows[0]))
+	values := make([][]any, len(rows))
+	for i, row := range rows {
+		values[i] = make([]any, len(columns))
+		for j, col := range columns {
+			values[i][j] = row[col]
+		}
+		if len(row) != len(columns) || !mapHasKeys(row, columns) {
+			return qb.fail(fmt.Errorf("InsertMaps: row %d has columns %v, want %v",
+				i, slices.Sorted(maps.Keys(row)), columns))
+		}
+	}
+	return qb.Insert(table, columns).MultiValues(values)
+}
+
+func mapHasKeys(m map[string]any, keys []string) bool {
+	for _, k := range keys {
+		if _, ok := m[k]; !ok {
+			return false
+		}
+	}
+	return true
+}
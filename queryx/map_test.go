@@ -0,0 +1,108 @@
+package queryx
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSetMap(t *testing.T) {
+	qb := NewQuery().
+		WithDialect(Postgres).
+		Update("users", nil).
+		SetMap(map[string]any{"name": "Ann", "age": 30, "email": nil}).
+		Where("id = ?", []any{1})
+
+	sql, args, err := qb.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedExpr := "UPDATE users SET age = $1, email = $2, name = $3 WHERE id = $4"
+	expectedArgs := []any{30, nil, "Ann", 1}
+
+	if sql != expectedExpr {
+		t.Errorf("expected SQL:\n%s\ngot:\n%s", expectedExpr, sql)
+	}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("expected args: %v, got: %v", expectedArgs, args)
+	}
+}
+
+func TestSetMap_SliceValues(t *testing.T) {
+	qb := NewQuery().
+		Update("posts", nil).
+		SetMap(map[string]any{"tags": []any{"a", "b"}, "labels": []any{"a"}}).
+		Where("id = ?", []any{1})
+
+	sql, args, err := qb.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedExpr := "UPDATE posts SET labels = ?, tags = ? WHERE id = ?"
+	expectedArgs := []any{[]any{"a"}, []any{"a", "b"}, 1}
+
+	if sql != expectedExpr {
+		t.Errorf("expected SQL:\n%s\ngot:\n%s", expectedExpr, sql)
+	}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("expected args: %v, got: %v", expectedArgs, args)
+	}
+}
+
+func TestInsertMap(t *testing.T) {
+	sql, args, err := NewQuery().InsertMap("users", map[string]any{"name": "Ann", "age": 30}).Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedExpr := "INSERT INTO users (age, name) VALUES (?, ?)"
+	expectedArgs := []any{30, "Ann"}
+
+	if sql != expectedExpr {
+		t.Errorf("expected SQL:\n%s\ngot:\n%s", expectedExpr, sql)
+	}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("expected args: %v, got: %v", expectedArgs, args)
+	}
+}
+
+func TestInsertMaps(t *testing.T) {
+	rows := []map[string]any{
+		{"name": "Ann", "age": 30},
+		{"age": 40, "name": "Bob"},
+	}
+
+	sql, args, err := NewQuery().InsertMaps("users", rows).Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedExpr := "INSERT INTO users (age, name) VALUES (?, ?), (?, ?)"
+	expectedArgs := []any{30, "Ann", 40, "Bob"}
+
+	if sql != expectedExpr {
+		t.Errorf("expected SQL:\n%s\ngot:\n%s", expectedExpr, sql)
+	}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("expected args: %v, got: %v", expectedArgs, args)
+	}
+}
+
+func TestInsertMaps_MismatchedColumns(t *testing.T) {
+	cases := [][]map[string]any{
+		{{"name": "Ann", "age": 30}, {"name": "Bob"}},
+		{{"name": "Ann", "age": 30}, {"name": "Bob", "email": "b@example.com"}},
+	}
+
+	for _, rows := range cases {
+		_, _, err := NewQuery().InsertMaps("users", rows).Build()
+		if err == nil {
+			t.Fatal("expected error for mismatched columns")
+		}
+		if !strings.Contains(err.Error(), "row 1 has columns") {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+}
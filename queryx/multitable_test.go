@@ -0,0 +1,179 @@
+package queryx
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMultiTable_Update(t *testing.T) {
+	cases := []struct {
+		name         string
+		qb           *QueryBuilder
+		expectedExpr string
+		expectedArgs []any
+	}{
+		{
+			"postgres from",
+			NewQuery().
+				WithDialect(Postgres).
+				Update("orders", []string{"status"}).
+				Values("void").
+				From("users u").
+				Where("u.id = orders.user_id", nil).
+				Where("u.banned = ?", []any{true}).
+				Returning("orders.id"),
+			"UPDATE orders SET status = $1 FROM users u WHERE u.id = orders.user_id AND u.banned = $2 RETURNING orders.id",
+			[]any{"void", true},
+		},
+		{
+			"postgres join",
+			NewQuery().
+				WithDialect(Postgres).
+				Update("orders", []string{"status"}).
+				Values("void").
+				Join("users u", "u.id = orders.user_id AND u.region = ?", []any{"eu"}).
+				LeftJoin("notes n", "n.user_id = u.id", nil).
+				Where("u.banned = ?", []any{true}),
+			"UPDATE orders SET status = $1 FROM users u LEFT JOIN notes n ON n.user_id = u.id WHERE u.id = orders.user_id AND u.region = $2 AND u.banned = $3",
+			[]any{"void", "eu", true},
+		},
+		{
+			"mysql join",
+			NewQuery().
+				WithDialect(MySQL).
+				Update("orders o", []string{"o.status"}).
+				Values("void").
+				Join("users u", "u.id = o.user_id", nil).
+				Where("u.banned = ?", []any{true}),
+			"UPDATE orders o INNER JOIN users u ON u.id = o.user_id SET o.status = ? WHERE u.banned = ?",
+			[]any{"void", true},
+		},
+		{
+			"mysql from",
+			NewQuery().
+				WithDialect(MySQL).
+				Update("orders", []string{"orders.status"}).
+				Values("void").
+				From("users").
+				Where("users.id = orders.user_id", nil),
+			"UPDATE orders, users SET orders.status = ? WHERE users.id = orders.user_id",
+			[]any{"void"},
+		},
+		{
+			"sqlserver join",
+			NewQuery().
+				WithDialect(SQLServer).
+				Update("orders", []string{"status"}).
+				Values("void").
+				Join("users u", "u.id = orders.user_id", nil).
+				Where("u.banned = ?", []any{true}).
+				Returning("id"),
+			"UPDATE orders SET status = @p1 OUTPUT INSERTED.id FROM users u WHERE u.id = orders.user_id AND u.banned = @p2",
+			[]any{"void", true},
+		},
+	}
+
+	for _, c := range cases {
+		sql, args, err := c.qb.Build()
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", c.name, err)
+		}
+		if sql != c.expectedExpr {
+			t.Errorf("%s: expected SQL:\n%s\ngot:\n%s", c.name, c.expectedExpr, sql)
+		}
+		if !reflect.DeepEqual(args, c.expectedArgs) {
+			t.Errorf("%s: expected args: %v, got: %v", c.name, c.expectedArgs, args)
+		}
+	}
+}
+
+func TestMultiTable_Delete(t *testing.T) {
+	cases := []struct {
+		name         string
+		qb           *QueryBuilder
+		expectedExpr string
+		expectedArgs []any
+	}{
+		{
+			"postgres using",
+			NewQuery().
+				WithDialect(Postgres).
+				Delete("sessions").
+				From("users").
+				Where("users.id = sessions.user_id AND users.banned = ?", []any{true}),
+			"DELETE FROM sessions USING users WHERE users.id = sessions.user_id AND users.banned = $1",
+			[]any{true},
+		},
+		{
+			"postgres join",
+			NewQuery().
+				WithDialect(Postgres).
+				Delete("sessions s").
+				Join("users u", "u.id = s.user_id", nil).
+				Where("u.banned = ?", []any{true}),
+			"DELETE FROM sessions s USING users u WHERE u.id = s.user_id AND u.banned = $1",
+			[]any{true},
+		},
+		{
+			"mysql join",
+			NewQuery().
+				WithDialect(MySQL).
+				QuoteIdentifiers().
+				Delete("sessions s").
+				Join("users u", "u.id = s.user_id", nil).
+				Where("u.banned = ?", []any{true}),
+			"DELETE `s` FROM `sessions` `s` INNER JOIN `users` `u` ON u.id = s.user_id WHERE u.banned = ?",
+			[]any{true},
+		},
+		{
+			"sqlserver join",
+			NewQuery().
+				WithDialect(SQLServer).
+				Delete("sessions").
+				Join("users", "users.id = sessions.user_id", nil).
+				Where("users.banned = ?", []any{true}).
+				Returning("id"),
+			"DELETE sessions OUTPUT DELETED.id FROM sessions INNER JOIN users ON users.id = sessions.user_id WHERE users.banned = @p1",
+			[]any{true},
+		},
+	}
+
+	for _, c := range cases {
+		sql, args, err := c.qb.Build()
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", c.name, err)
+		}
+		if sql != c.expectedExpr {
+			t.Errorf("%s: expected SQL:\n%s\ngot:\n%s", c.name, c.expectedExpr, sql)
+		}
+		if !reflect.DeepEqual(args, c.expectedArgs) {
+			t.Errorf("%s: expected args: %v, got: %v", c.name, c.expectedArgs, args)
+		}
+	}
+}
+
+func TestMultiTable_Errors(t *testing.T) {
+	cases := []struct {
+		name string
+		qb   *QueryBuilder
+	}{
+		{
+			"oracle update",
+			NewQuery().WithDialect(Oracle).Update("a", []string{"x"}).Values(1).From("b").Where("b.id = a.id", nil),
+		},
+		{
+			"sqlite delete",
+			NewQuery().WithDialect(SQLite).Delete("a").Join("b", "b.id = a.id", nil).Where("b.x = ?", []any{1}),
+		},
+		{
+			"postgres left join first",
+			NewQuery().WithDialect(Postgres).Update("a", []string{"x"}).Values(1).LeftJoin("b", "b.id = a.id", nil),
+		},
+	}
+
+	for _, c := range cases {
+		if _, _, err := c.qb.Build(); err == nil {
+			t.Errorf("%s: expected error", c.name)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package queryx
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+)
+
+// Page is a page of rows returned by Paginate.
+type Page[T any] struct {
+	Items []T
+	// Total is the number of rows matched across all pages, or -1 when the
+	// count was skipped with SkipCount.
+	Total   int64
+	Page    int
+	Size    int
+	HasNext bool
+}
+
+// SkipCount makes Paginate skip the count query. HasNext is still set.
+func SkipCount() Option {
+	return func(o *options) {
+		o.skipCount = true
+	}
+}
+
+// Concurrent makes Paginate run the count and the data queries at the same
+// time, on separate connections of a *sql.DB. It has no effect when the
+// runner is bound to a single connection, such as a transaction.
+func Concurrent() Option {
+	return func(o *options) {
+		o.concurrent = true
+	}
+}
+
+// Paginate returns the given page, starting at 1, of size rows of qb along
+// with the total given by qb.CountTotal. The limit and offset of qb are
+// replaced; qb itself is left unchanged.
+//
+//	page, err := queryx.Paginate[User](ctx, db, qb.OrderBy("id"), 2, 50)
+//
+// One row more than size is fetched to set HasNext without relying on the
+// count.
+func Paginate[T any](ctx context.Context, r Runner, qb *QueryBuilder, page, size int, opts ...Option) (Page[T], error) {
+	if page < 1 || size < 1 {
+		return Page[T]{}, fmt.Errorf("Paginate: invalid page %d of size %d", page, size)
+	}
+	o := newOptions(opts)
+
+	data := *qb
+	data.Limit(size + 1)
+	data.offsetClause = nil
+	if page > 1 {
+		data.Offset((page - 1) * size)
+	}
+
+	result := Page[T]{Total: -1, Page: page, Size: size}
+	var countErr error
+	var wg sync.WaitGroup
+	count := func() {
+		result.Total, countErr = One[int64](ctx, r, qb.CountTotal())
+	}
+
+	switch {
+	case o.skipCount:
+	case o.concurrent && !singleConn(r):
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			count()
+		}()
+	default:
+		if count(); countErr != nil {
+			return Page[T]{}, countErr
+		}
+	}
+
+	items, err := All[T](ctx, r, &data, opts...)
+	wg.Wait()
+	if err != nil {
+		return Page[T]{}, err
+	}
+	if countErr != nil {
+		return Page[T]{}, countErr
+	}
+
+	if len(items) > size {
+		items, result.HasNext = items[:size], true
+	}
+	result.Items = items
+	return result, nil
+}
+
+// singleConn reports whether r runs every statement on the same connection,
+// which cannot serve two queries at once.
+func singleConn(r Runner) bool {
+	if dr, ok := r.(dialectRunner); ok {
+		r = dr.Runner
+	}
+	switch r.(type) {
+	case *txRunner, *sql.Tx, *sql.Conn:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+package queryx
+
+import (
+	"context"
+	"database/sql/driver"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// pagedHandler answers count queries with total and other queries with ids
+// starting at first.
+func pagedHandler(total int64, ids ...int64) func(string, []driver.Value) (*fakeResult, error) {
+	return func(query string, args []driver.Value) (*fakeResult, error) {
+		if strings.HasPrefix(query, "SELECT COUNT(*)") {
+			return &fakeResult{columns: []string{"count"}, rows: [][]driver.Value{{total}}}, nil
+		}
+		res := &fakeResult{columns: []string{"id"}}
+		for _, id := range ids {
+			res.rows = append(res.rows, []driver.Value{id})
+		}
+		return res, nil
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	db, fdb := newFakeDB(t, pagedHandler(5, 3, 4, 5))
+	qb := NewQuery().Select("id").From("users").Where("active = ?", []any{true}).OrderBy("id")
+
+	page, err := Paginate[int64](context.Background(), NewRunner(db, Postgres), qb, 2, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := Page[int64]{Items: []int64{3, 4}, Total: 5, Page: 2, Size: 2, HasNext: true}
+	if !reflect.DeepEqual(page, expected) {
+		t.Errorf("expected %+v, got %+v", expected, page)
+	}
+
+	queries := []string{
+		"SELECT COUNT(*) FROM users WHERE active = $1",
+		"SELECT id FROM users WHERE active = $1 ORDER BY id LIMIT $2 OFFSET $3",
+	}
+	if !reflect.DeepEqual(fdb.queries(), queries) {
+		t.Errorf("expected %q, got %q", queries, fdb.queries())
+	}
+	if args := fdb.lastCall().args; !reflect.DeepEqual(args, []driver.Value{true, int64(3), int64(2)}) {
+		t.Errorf("unexpected args %v", args)
+	}
+
+	if _, _, err := qb.Build(); err != nil || qb.limitClause != nil {
+		t.Error("expected Paginate to leave the builder unchanged")
+	}
+}
+
+func TestPaginate_SkipCountAndConcurrent(t *testing.T) {
+	db, fdb := newFakeDB(t, pagedHandler(3, 3))
+	qb := NewQuery().Select("id").From("users").OrderBy("id")
+
+	page, err := Paginate[int64](context.Background(), db, qb, 2, 2, SkipCount())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := Page[int64]{Items: []int64{3}, Total: -1, Page: 2, Size: 2}
+	if !reflect.DeepEqual(page, expected) {
+		t.Errorf("expected %+v, got %+v", expected, page)
+	}
+	if len(fdb.queries()) != 1 {
+		t.Errorf("expected the count to be skipped, got %q", fdb.queries())
+	}
+
+	page, err = Paginate[int64](context.Background(), db, qb, 2, 2, Concurrent())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if page.Total != 3 || page.HasNext {
+		t.Errorf("unexpected page %+v", page)
+	}
+
+	if _, err := Paginate[int64](context.Background(), db, qb, 0, 2); err == nil {
+		t.Error("expected an error for page 0")
+	}
+}
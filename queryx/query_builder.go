@@ -12,6 +12,7 @@ package queryx
 
 import (
 	"errors"
+	"fmt"
 	"slices"
 	"strings"
 
@@ -19,37 +20,114 @@ import (
 )
 
 type QueryBuilder struct {
-	isCount           bool
-	selectClause      *clauses.Select
-	insertClause      *clauses.Insert
-	updateClause      *clauses.Update
-	valuesClause      *clauses.Values
-	multiValuesClause *clauses.MultiValues
-	deleteClause      *clauses.Delete
-	fromClause        *clauses.From
-	whereClause       []*clauses.Where
-	havingClause      []*clauses.Having
-	joinClause        []*clauses.Join
-	orderByClause     *clauses.OrderBy
-	groupByClause     *clauses.GroupBy
-	limitClause       *clauses.Limit
-	offsetClause      *clauses.Offset
+	isCount              bool
+	isExists             bool
+	countDistinct        string
+	withClause           []*clauses.With
+	setOperations        []*clauses.SetOperation
+	setClause            []*clauses.Assignment
+	selectClause         *clauses.Select
+	insertClause         *clauses.Insert
+	updateClause         *clauses.Update
+	valuesClause         *clauses.Values
+	multiValuesClause    *clauses.MultiValues
+	insertSelectClause   *clauses.InsertSelect
+	onConflictClause     *clauses.OnConflict
+	onDuplicateKeyClause *clauses.OnDuplicateKey
+	returningClause      *clauses.Returning
+	deleteClause         *clauses.Delete
+	fromClause           *clauses.From
+	whereClause          []*clauses.Where
+	havingClause         []*clauses.Having
+	windows              []namedWindow
+	joinClause           []*clauses.Join
+	orderByClause        *clauses.OrderBy
+	seekKeys             []SortKey
+	groupByClause        *clauses.GroupBy
+	limitClause          *clauses.Limit
+	offsetClause         *clauses.Offset
+	dialect              Dialect
+	quoteIdents          bool
+	err                  error
 }
 
 func NewQuery() *QueryBuilder {
 	return &QueryBuilder{}
 }
 
+// WithDialect sets the dialect used by Build to render placeholders.
+func (qb *QueryBuilder) WithDialect(d Dialect) *QueryBuilder {
+	qb.dialect = d
+	return qb
+}
+
+// QuoteIdentifiers enables quoting of table and column names with the
+// dialect's identifier quotes. Only plain identifiers are quoted, including
+// "schema.table", "table.column" and "col AS alias" forms; expressions such
+// as "COUNT(*)" are left as written.
+func (qb *QueryBuilder) QuoteIdentifiers() *QueryBuilder {
+	qb.quoteIdents = true
+	return qb
+}
+
+// With adds a common table expression, rendered ahead of the statement as
+// "WITH name AS (query)". Its arguments come before those of the statement.
+func (qb *QueryBuilder) With(name string, query *QueryBuilder) *QueryBuilder {
+	qb.withClause = append(qb.withClause, clauses.NewWith(name, query))
+	return qb
+}
+
+// WithRecursive adds a recursive common table expression whose body is
+// "anchor UNION ALL recursive". The recursive member refers to the CTE by
+// name, e.g. in its From or Join.
+func (qb *QueryBuilder) WithRecursive(name string, columns []string, anchor, recursive *QueryBuilder) *QueryBuilder {
+	qb.withClause = append(qb.withClause, clauses.NewWithRecursive(name, columns, anchor, recursive))
+	return qb
+}
+
 func (qb *QueryBuilder) Insert(table string, columns []string) *QueryBuilder {
 	qb.insertClause = clauses.NewInsert(table, columns)
 	return qb
 }
 
+// Update starts an update of table. Columns listed here take their values,
+// in order, from Values; use nil columns to rely only on Set, SetExpr,
+// Increment and Decrement.
 func (qb *QueryBuilder) Update(table string, columns []string) *QueryBuilder {
 	qb.updateClause = clauses.NewUpdate(table, columns)
 	return qb
 }
 
+// Set assigns value to column in an update, rendering "column = ?". A
+// *QueryBuilder value is rendered as a scalar subquery and an Expr in place.
+// Other values, slices included, are bound to the placeholder as they are.
+func (qb *QueryBuilder) Set(column string, value any) *QueryBuilder {
+	switch value := value.(type) {
+	case *QueryBuilder:
+		return qb.SetExpr(column, "(?)", []any{value})
+	case Expr:
+		return qb.SetExpr(column, "?", []any{value})
+	}
+	return qb.SetExpr(column, "?", []any{bindValue{value}})
+}
+
+// SetExpr assigns a SQL expression to column in an update, as in
+// SetExpr("views", "views + ?", []any{1}) or SetExpr("updated_at", "NOW()", nil).
+func (qb *QueryBuilder) SetExpr(column, expr string, args []any) *QueryBuilder {
+	qb.setClause = append(qb.setClause, clauses.NewAssignment(column, expr, args))
+	return qb
+}
+
+// Increment renders "column = column + ?".
+func (qb *QueryBuilder) Increment(column string, n any) *QueryBuilder {
+	return qb.SetExpr(column, "? + ?", []any{columnRef{column}, n})
+}
+
+// Decrement renders "column = column - ?".
+func (qb *QueryBuilder) Decrement(column string, n any) *QueryBuilder {
+	return qb.SetExpr(column, "? - ?", []any{columnRef{column}, n})
+}
+
 func (qb *QueryBuilder) Values(values ...any) *QueryBuilder {
 	qb.valuesClause = clauses.NewValues(values)
 	return qb
@@ -60,6 +138,108 @@ func (qb *QueryBuilder) MultiValues(values [][]any) *QueryBuilder {
 	return qb
 }
 
+// FromSelect fills an insert with the rows of sub, rendering
+// "INSERT INTO t (cols) SELECT ...". When the select list of sub has a known
+// size, Build checks that it matches the insert columns.
+func (qb *QueryBuilder) FromSelect(sub *QueryBuilder) *QueryBuilder {
+	qb.insertSelectClause = clauses.NewInsertSelect(sub)
+	return qb
+}
+
+// OnConflict starts an upsert clause for Postgres and SQLite, targeting the
+// unique constraint made of columns. Complete it with DoNothing or
+// DoUpdateSet:
+//
+//	qb.Insert("users", []string{"email", "name"}).
+//	    Values("a@b.c", "Ann").
+//	    OnConflict("email").
+//	    DoUpdateSet("name")
+//
+// renders "... ON CONFLICT (email) DO UPDATE SET name = EXCLUDED.name".
+func (qb *QueryBuilder) OnConflict(columns ...string) *QueryBuilder {
+	qb.onConflictClause = clauses.NewOnConflict(columns)
+	return qb
+}
+
+// DoNothing makes conflicting inserts a no-op. Without OnConflict columns it
+// renders "ON CONFLICT DO NOTHING", which ignores any constraint violation.
+func (qb *QueryBuilder) DoNothing() *QueryBuilder {
+	qb.conflict().DoNothing = true
+	return qb
+}
+
+// DoUpdateSet overwrites columns of the existing row with the values that
+// were proposed for insertion.
+func (qb *QueryBuilder) DoUpdateSet(columns ...string) *QueryBuilder {
+	c := qb.conflict()
+	for _, col := range columns {
+		c.Set = append(c.Set, clauses.NewAssignment(col, "?", []any{Excluded(col)}))
+	}
+	return qb
+}
+
+// DoUpdateSetExpr assigns an expression to column when the insert
+// conflicts, as in DoUpdateSetExpr("hits", "users.hits + ?", []any{1}).
+// Use Excluded as an argument to refer to the proposed row.
+func (qb *QueryBuilder) DoUpdateSetExpr(column, expr string, args []any) *QueryBuilder {
+	c := qb.conflict()
+	c.Set = append(c.Set, clauses.NewAssignment(column, expr, args))
+	return qb
+}
+
+// DoUpdateWhere limits the conflict update to rows matching condition.
+func (qb *QueryBuilder) DoUpdateWhere(condition string, args []any) *QueryBuilder {
+	c := qb.conflict()
+	c.Condition = condition
+	c.Args = args
+	return qb
+}
+
+func (qb *QueryBuilder) conflict() *clauses.OnConflict {
+	if qb.onConflictClause == nil {
+		qb.onConflictClause = clauses.NewOnConflict(nil)
+	}
+	return qb.onConflictClause
+}
+
+// OnDuplicateKeyUpdate is the MySQL upsert form. Each column is set to the
+// value proposed for insertion with "column = VALUES(column)".
+func (qb *QueryBuilder) OnDuplicateKeyUpdate(columns ...string) *QueryBuilder {
+	c := qb.duplicateKey()
+	for _, col := range columns {
+		c.Set = append(c.Set, clauses.NewAssignment(col, "?", []any{Excluded(col)}))
+	}
+	return qb
+}
+
+// OnDuplicateKeyUpdateExpr assigns an expression to column when the insert
+// hits a duplicate key.
+func (qb *QueryBuilder) OnDuplicateKeyUpdateExpr(column, expr string, args []any) *QueryBuilder {
+	c := qb.duplicateKey()
+	c.Set = append(c.Set, clauses.NewAssignment(column, expr, args))
+	return qb
+}
+
+func (qb *QueryBuilder) duplicateKey() *clauses.OnDuplicateKey {
+	if qb.onDuplicateKeyClause == nil {
+		qb.onDuplicateKeyClause = clauses.NewOnDuplicateKey()
+	}
+	return qb.onDuplicateKeyClause
+}
+
+// Returning makes an insert, update or delete return the given columns of
+// the affected rows, or every column when none are given. It renders
+// "RETURNING ..." on Postgres and SQLite and an OUTPUT clause reading from
+// INSERTED or DELETED on SQL Server; other dialects return an error from
+// Build.
+func (qb *QueryBuilder) Returning(columns ...string) *QueryBuilder {
+	if len(columns) == 0 {
+		columns = []string{"*"}
+	}
+	qb.returningClause = clauses.NewReturning(columns)
+	return qb
+}
+
 func (qb *QueryBuilder) Delete(table string) *QueryBuilder {
 	qb.deleteClause = clauses.NewDelete(table)
 	return qb
@@ -74,31 +254,124 @@ func (qb *QueryBuilder) CountTotal() *QueryBuilder {
 	return newQb
 }
 
+// CountDistinct derives a query counting the distinct values of column
+// among the rows of qb, as "SELECT COUNT(DISTINCT column) ...". When qb is
+// wrapped in a subquery, as for grouped or compound queries, column must
+// name one of its output columns.
+func (qb *QueryBuilder) CountDistinct(column string) *QueryBuilder {
+	newQb := qb.CountTotal()
+	newQb.countDistinct = column
+	return newQb
+}
+
+// Exists derives a query reporting whether qb matches any row, rendered as
+// "SELECT EXISTS (SELECT ...)". Dialects that cannot select a boolean get
+// "SELECT CASE WHEN EXISTS (SELECT ...) THEN 1 ELSE 0 END" instead.
+func (qb *QueryBuilder) Exists() *QueryBuilder {
+	newQb := qb.cloneForCount()
+	newQb.isExists = true
+	return newQb
+}
+
 func (qb *QueryBuilder) Select(columns ...string) *QueryBuilder {
 	qb.selectClause = clauses.NewSelect(columns...)
 	return qb
 }
 
+// SelectSubquery adds a scalar subquery column, rendered as
+// "(SELECT ...) AS alias", after any columns set with Select.
+func (qb *QueryBuilder) SelectSubquery(sub *QueryBuilder, alias string) *QueryBuilder {
+	if qb.selectClause == nil {
+		qb.selectClause = clauses.NewSelect()
+	}
+	qb.selectClause.Columns = append(slices.Clip(qb.selectClause.Columns), "(?) AS "+alias)
+	qb.selectClause.Args = append(qb.selectClause.Args, sub)
+	return qb
+}
+
+// SelectExpr adds an expression column, such as a window function computed
+// with Over, rendered as "expr AS alias" after any columns set with Select.
+func (qb *QueryBuilder) SelectExpr(expr Expr, alias string) *QueryBuilder {
+	if qb.selectClause == nil {
+		qb.selectClause = clauses.NewSelect()
+	}
+	qb.selectClause.Columns = append(slices.Clip(qb.selectClause.Columns), "? AS "+alias)
+	qb.selectClause.Args = append(qb.selectClause.Args, expr)
+	return qb
+}
+
 func (qb *QueryBuilder) From(table string) *QueryBuilder {
 	qb.fromClause = clauses.NewFrom(table)
 	return qb
 }
 
+// FromSubquery selects from a derived table, rendered as
+// "FROM (SELECT ...) AS alias".
+func (qb *QueryBuilder) FromSubquery(sub *QueryBuilder, alias string) *QueryBuilder {
+	qb.fromClause = clauses.NewFromSubquery(sub, alias)
+	return qb
+}
+
+// Where adds a condition with "?" placeholders. Conditions are joined with
+// AND. Slice arguments expand into one placeholder per element, so
+// Where("id IN (?)", []any{ids}) works with any dialect; an empty slice makes
+// Build fail, use WhereCond(In(...)) to match no rows instead.
 func (qb *QueryBuilder) Where(condition string, args []any) *QueryBuilder {
 	qb.whereClause = append(qb.whereClause, clauses.NewWhere(condition, args))
 	return qb
 }
 
+// WhereCond adds a condition built with the Cond API. Like Where, multiple
+// conditions are joined with AND. A nil condition makes Build fail.
+func (qb *QueryBuilder) WhereCond(cond Cond) *QueryBuilder {
+	if cond == nil {
+		return qb.fail(errors.New("WhereCond: nil condition"))
+	}
+	return qb.Where("?", []any{cond})
+}
+
 func (qb *QueryBuilder) GroupBy(columns ...string) *QueryBuilder {
 	qb.groupByClause = clauses.NewGroupBy(columns...)
 	return qb
 }
 
+// GroupByRollup groups by columns and adds subtotal rows for each prefix of
+// them, down to the grand total: "GROUP BY ROLLUP (a, b)", or
+// "GROUP BY a, b WITH ROLLUP" on MySQL.
+func (qb *QueryBuilder) GroupByRollup(columns ...string) *QueryBuilder {
+	qb.groupByClause = clauses.NewGroupByRollup(columns...)
+	return qb
+}
+
+// GroupByCube groups by columns and adds subtotal rows for every
+// combination of them: "GROUP BY CUBE (a, b)".
+func (qb *QueryBuilder) GroupByCube(columns ...string) *QueryBuilder {
+	qb.groupByClause = clauses.NewGroupByCube(columns...)
+	return qb
+}
+
+// GroupingSets groups by each of sets in turn, as in
+// "GROUP BY GROUPING SETS ((a, b), (a), ())". An empty set stands for the
+// grand total.
+func (qb *QueryBuilder) GroupingSets(sets ...[]string) *QueryBuilder {
+	qb.groupByClause = clauses.NewGroupingSets(sets...)
+	return qb
+}
+
 func (qb *QueryBuilder) Having(condition string, args []any) *QueryBuilder {
 	qb.havingClause = append(qb.havingClause, clauses.NewHaving(condition, args))
 	return qb
 }
 
+// HavingCond adds a having condition built with the Cond API. A nil
+// condition makes Build fail.
+func (qb *QueryBuilder) HavingCond(cond Cond) *QueryBuilder {
+	if cond == nil {
+		return qb.fail(errors.New("HavingCond: nil condition"))
+	}
+	return qb.Having("?", []any{cond})
+}
+
 func (qb *QueryBuilder) OrderBy(columns ...string) *QueryBuilder {
 	qb.orderByClause = clauses.NewOrderBy(columns...)
 	return qb
@@ -114,6 +387,35 @@ func (qb *QueryBuilder) Offset(offset any) *QueryBuilder {
 	return qb
 }
 
+// Union combines the result of qb with each of the given queries using
+// UNION. OrderBy, Limit and Offset set on qb apply to the combined result;
+// the other queries are wrapped in parentheses when they have their own.
+func (qb *QueryBuilder) Union(queries ...*QueryBuilder) *QueryBuilder {
+	return qb.addSetOperations(clauses.Union, queries)
+}
+
+// UnionAll combines queries like Union but keeps duplicate rows.
+func (qb *QueryBuilder) UnionAll(queries ...*QueryBuilder) *QueryBuilder {
+	return qb.addSetOperations(clauses.UnionAll, queries)
+}
+
+// Intersect keeps the rows returned by qb and by each of the given queries.
+func (qb *QueryBuilder) Intersect(queries ...*QueryBuilder) *QueryBuilder {
+	return qb.addSetOperations(clauses.Intersect, queries)
+}
+
+// Except removes the rows returned by any of the given queries.
+func (qb *QueryBuilder) Except(queries ...*QueryBuilder) *QueryBuilder {
+	return qb.addSetOperations(clauses.Except, queries)
+}
+
+func (qb *QueryBuilder) addSetOperations(op string, queries []*QueryBuilder) *QueryBuilder {
+	for _, query := range queries {
+		qb.setOperations = append(qb.setOperations, clauses.NewSetOperation(op, query))
+	}
+	return qb
+}
+
 func (qb *QueryBuilder) Join(table, condition string, args []any) *QueryBuilder {
 	qb.joinClause = append(qb.joinClause, clauses.NewInnerJoin(table, condition, args))
 	return qb
@@ -124,98 +426,476 @@ func (qb *QueryBuilder) LeftJoin(table, condition string, args []any) *QueryBuil
 	return qb
 }
 
+func (qb *QueryBuilder) RightJoin(table, condition string, args []any) *QueryBuilder {
+	qb.joinClause = append(qb.joinClause, clauses.NewRightJoin(table, condition, args))
+	return qb
+}
+
+// FullJoin adds a FULL OUTER JOIN. MySQL does not support it and Build
+// returns an error there.
+func (qb *QueryBuilder) FullJoin(table, condition string, args []any) *QueryBuilder {
+	qb.joinClause = append(qb.joinClause, clauses.NewFullJoin(table, condition, args))
+	return qb
+}
+
+func (qb *QueryBuilder) CrossJoin(table string) *QueryBuilder {
+	qb.joinClause = append(qb.joinClause, clauses.NewCrossJoin(table))
+	return qb
+}
+
+// JoinUsing adds an INNER JOIN matching rows on the given shared columns,
+// rendered as "INNER JOIN table USING (col, ...)".
+func (qb *QueryBuilder) JoinUsing(table string, columns ...string) *QueryBuilder {
+	qb.joinClause = append(qb.joinClause, clauses.NewJoinUsing(clauses.InnerJoin, table, columns))
+	return qb
+}
+
+// LeftJoinUsing adds a LEFT JOIN matching rows on the given shared columns.
+func (qb *QueryBuilder) LeftJoinUsing(table string, columns ...string) *QueryBuilder {
+	qb.joinClause = append(qb.joinClause, clauses.NewJoinUsing(clauses.LeftJoin, table, columns))
+	return qb
+}
+
+// RightJoinUsing adds a RIGHT JOIN matching rows on the given shared columns.
+func (qb *QueryBuilder) RightJoinUsing(table string, columns ...string) *QueryBuilder {
+	qb.joinClause = append(qb.joinClause, clauses.NewJoinUsing(clauses.RightJoin, table, columns))
+	return qb
+}
+
+// FullJoinUsing adds a FULL OUTER JOIN matching rows on the given shared
+// columns.
+func (qb *QueryBuilder) FullJoinUsing(table string, columns ...string) *QueryBuilder {
+	qb.joinClause = append(qb.joinClause, clauses.NewJoinUsing(clauses.FullJoin, table, columns))
+	return qb
+}
+
+// JoinSubquery adds an INNER JOIN against a derived table built from sub.
+func (qb *QueryBuilder) JoinSubquery(sub *QueryBuilder, alias, condition string, args []any) *QueryBuilder {
+	qb.joinClause = append(qb.joinClause, clauses.NewJoinSubquery(clauses.InnerJoin, sub, alias, condition, args))
+	return qb
+}
+
+// LeftJoinSubquery adds a LEFT JOIN against a derived table built from sub.
+func (qb *QueryBuilder) LeftJoinSubquery(sub *QueryBuilder, alias, condition string, args []any) *QueryBuilder {
+	qb.joinClause = append(qb.joinClause, clauses.NewJoinSubquery(clauses.LeftJoin, sub, alias, condition, args))
+	return qb
+}
+
+// JoinLateral adds an INNER JOIN LATERAL against sub, which may reference
+// columns of the tables before it.
+func (qb *QueryBuilder) JoinLateral(sub *QueryBuilder, alias, condition string, args []any) *QueryBuilder {
+	qb.joinClause = append(qb.joinClause, clauses.NewLateralJoin(clauses.InnerJoin, sub, alias, condition, args))
+	return qb
+}
+
+// LeftJoinLateral adds a LEFT JOIN LATERAL against sub. An empty condition
+// renders "ON 1=1".
+func (qb *QueryBuilder) LeftJoinLateral(sub *QueryBuilder, alias, condition string, args []any) *QueryBuilder {
+	qb.joinClause = append(qb.joinClause, clauses.NewLateralJoin(clauses.LeftJoin, sub, alias, condition, args))
+	return qb
+}
+
+// CrossJoinLateral adds a CROSS JOIN LATERAL against sub.
+func (qb *QueryBuilder) CrossJoinLateral(sub *QueryBuilder, alias string) *QueryBuilder {
+	qb.joinClause = append(qb.joinClause, clauses.NewLateralJoin(clauses.CrossJoin, sub, alias, "", nil))
+	return qb
+}
+
+// JoinCond adds an INNER JOIN whose ON condition is built with the Cond API.
+func (qb *QueryBuilder) JoinCond(table string, on Cond) *QueryBuilder {
+	if on == nil {
+		return qb.fail(errors.New("JoinCond: nil condition"))
+	}
+	return qb.Join(table, "?", []any{on})
+}
+
+// LeftJoinCond adds a LEFT JOIN whose ON condition is built with the Cond API.
+func (qb *QueryBuilder) LeftJoinCond(table string, on Cond) *QueryBuilder {
+	if on == nil {
+		return qb.fail(errors.New("LeftJoinCond: nil condition"))
+	}
+	return qb.LeftJoin(table, "?", []any{on})
+}
+
 func (qb *QueryBuilder) Build() (string, []any, error) {
+	query, args, err := qb.build()
+	if err != nil {
+		return "", nil, err
+	}
+	return bindPlaceholders(qb.getDialect(), query), args, nil
+}
+
+func (qb *QueryBuilder) build() (string, []any, error) {
+	if qb.err != nil {
+		return "", nil, qb.err
+	}
+
 	var sqlBuilder strings.Builder
-	var args []any
+
+	args, err := buildWith(qb, &sqlBuilder, nil)
+	if err != nil {
+		return "", nil, err
+	}
 
 	switch {
+	case qb.isExists:
+		args, err = qb.buildExistsStatement(&sqlBuilder, args)
 	case qb.isCount:
-		if qb.groupByClause != nil && len(qb.groupByClause.Columns) > 0 {
-			sqlBuilder.WriteString("SELECT COUNT(*) FROM (SELECT 1")
-			buildFrom(qb, &sqlBuilder)
-			args = buildJoins(qb, &sqlBuilder, args)
-			args = buildWhere(qb, &sqlBuilder, args)
-			sqlBuilder.WriteString(") AS subquery")
-		} else {
-			sqlBuilder.WriteString("SELECT COUNT(*)")
-			buildFrom(qb, &sqlBuilder)
-			args = buildJoins(qb, &sqlBuilder, args)
-			args = buildWhere(qb, &sqlBuilder, args)
-		}
+		args, err = qb.buildCountStatement(&sqlBuilder, args)
 	case qb.insertClause != nil:
-		if qb.insertClause.Table == "" {
-			return "", nil, errors.New("insert requires a table name")
-		}
-		if len(qb.insertClause.Columns) == 0 {
-			return "", nil, errors.New("insert requires columns")
-		}
-		if qb.valuesClause == nil && qb.multiValuesClause == nil {
-			return "", nil, errors.New("insert requires values or multi-values")
+		args, err = qb.buildInsertStatement(&sqlBuilder, args)
+	case qb.updateClause != nil:
+		args, err = qb.buildUpdateStatement(&sqlBuilder, args)
+	case qb.deleteClause != nil:
+		args, err = qb.buildDeleteStatement(&sqlBuilder, args)
+	case qb.selectClause != nil:
+		args, err = qb.buildSelectStatement(&sqlBuilder, args)
+	default:
+		return "", nil, errors.New("no query type specified (select/insert/update)")
+	}
+	if err != nil {
+		return "", nil, err
+	}
+
+	return sqlBuilder.String(), args, nil
+}
+
+func (qb *QueryBuilder) buildCountStatement(b *strings.Builder, args []any) ([]any, error) {
+	count := "COUNT(*)"
+	if qb.countDistinct != "" {
+		count = "COUNT(DISTINCT " + quoteColumn(qb, qb.countDistinct) + ")"
+	}
+	grouped := qb.groupByClause != nil && (len(qb.groupByClause.Columns) > 0 || len(qb.groupByClause.Sets) > 0) ||
+		len(qb.havingClause) > 0
+	hasColumns := qb.selectClause != nil && len(qb.selectClause.Columns) > 0
+
+	// The rows of the original select are counted when they are
+	// deduplicated, when the distinct column must come out of the groups, or
+	// when HAVING may refer to select aliases. Other groups are counted
+	// through "SELECT 1", which needs no select clause and leaves out the
+	// select columns, whose names may clash or be missing.
+	wrap := len(qb.setOperations) > 0 || qb.selectsDistinct() ||
+		grouped && hasColumns && qb.countDistinct != "" ||
+		len(qb.havingClause) > 0 && hasColumns && qb.getDialect().Supports(FeatureHavingAliases)
+	switch {
+	case wrap:
+		b.WriteString("SELECT " + count + " FROM (")
+		args, err := qb.buildSelectBody(b, args)
+		if err != nil {
+			return nil, err
 		}
-		args = buildInsert(qb, &sqlBuilder, args)
-		if qb.multiValuesClause != nil {
-			args = buildMultiValues(qb, &sqlBuilder, args)
-		} else {
-			args = buildValues(qb, &sqlBuilder, args)
+		b.WriteString(")" + tableAlias(qb, "subquery"))
+		return args, nil
+	case grouped:
+		b.WriteString("SELECT " + count + " FROM (SELECT 1")
+		args, err := buildClauses(qb, b, args, buildFrom, buildJoins, buildWhere, buildGroupBy, buildHaving)
+		if err != nil {
+			return nil, err
 		}
+		b.WriteString(")" + tableAlias(qb, "subquery"))
+		return args, nil
+	}
 
-	case qb.updateClause != nil:
-		if qb.updateClause.Table == "" {
-			return "", nil, errors.New("update requires a table name")
+	b.WriteString("SELECT " + count)
+	return buildClauses(qb, b, args, buildFrom, buildJoins, buildWhere)
+}
+
+func (qb *QueryBuilder) buildExistsStatement(b *strings.Builder, args []any) ([]any, error) {
+	d := qb.getDialect()
+	if d.Supports(FeatureSelectExists) {
+		b.WriteString("SELECT EXISTS (")
+	} else {
+		b.WriteString("SELECT CASE WHEN EXISTS (")
+	}
+	args, err := qb.buildSelectBody(b, args)
+	if err != nil {
+		return nil, err
+	}
+	if d.Supports(FeatureSelectExists) {
+		b.WriteString(")")
+	} else {
+		b.WriteString(") THEN 1 ELSE 0 END")
+	}
+	if d.Supports(FeatureFromDual) {
+		b.WriteString(" FROM DUAL")
+	}
+	return args, nil
+}
+
+func (qb *QueryBuilder) buildInsertStatement(b *strings.Builder, args []any) ([]any, error) {
+	if qb.insertClause.Table == "" {
+		return nil, errors.New("insert requires a table name")
+	}
+	if len(qb.insertClause.Columns) == 0 {
+		return nil, errors.New("insert requires columns")
+	}
+	sources := 0
+	for _, set := range []bool{qb.valuesClause != nil, qb.multiValuesClause != nil, qb.insertSelectClause != nil} {
+		if set {
+			sources++
 		}
-		if len(qb.updateClause.Columns) == 0 {
-			return "", nil, errors.New("update requires columns")
+	}
+	if sources == 0 {
+		return nil, errors.New("insert requires values, multi-values or a select")
+	}
+	if sources > 1 {
+		return nil, errors.New("insert accepts only one of values, multi-values or a select")
+	}
+	if qb.onConflictClause != nil && qb.onDuplicateKeyClause != nil {
+		return nil, errors.New("on conflict and on duplicate key update cannot be combined")
+	}
+
+	args = buildInsert(qb, b, args)
+	args, err := buildOutput("INSERTED")(qb, b, args)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case qb.insertSelectClause != nil:
+		if args, err = buildInsertSelect(qb, b, args); err != nil {
+			return nil, err
 		}
+	case qb.multiValuesClause != nil:
+		args = buildMultiValues(qb, b, args)
+	default:
+		args = buildValues(qb, b, args)
+	}
+	return buildClauses(qb, b, args, buildUpsert, buildReturning)
+}
+
+func (qb *QueryBuilder) buildUpdateStatement(b *strings.Builder, args []any) ([]any, error) {
+	if qb.updateClause.Table == "" {
+		return nil, errors.New("update requires a table name")
+	}
+	if len(qb.updateClause.Columns) == 0 && len(qb.setClause) == 0 {
+		return nil, errors.New("update requires columns")
+	}
+	if len(qb.updateClause.Columns) > 0 {
 		if qb.valuesClause == nil {
-			return "", nil, errors.New("update requires values")
+			return nil, errors.New("update requires values")
 		}
 		if len(qb.valuesClause.Args) != len(qb.updateClause.Columns) {
-			return "", nil, errors.New("number of values must match columns in update")
+			return nil, errors.New("number of values must match columns in update")
 		}
-		args = buildUpdate(qb, &sqlBuilder, args)
-		args = append(args, qb.valuesClause.Args...)
-		args = buildWhere(qb, &sqlBuilder, args)
+	}
 
-	case qb.deleteClause != nil:
-		if qb.deleteClause.Table == "" {
-			return "", nil, errors.New("delete requires a table name")
-		}
-		args = buildDelete(qb, &sqlBuilder, args)
+	if qb.fromClause == nil && len(qb.joinClause) == 0 {
+		return buildClauses(qb, b, args, buildUpdate, buildOutput("INSERTED"), buildWhere, buildReturning)
+	}
 
-		if len(qb.whereClause) == 0 {
-			return "", nil, errors.New("delete requires where condition")
+	d := qb.getDialect()
+	switch {
+	case d.Supports(FeatureUpdateJoin):
+		b.WriteString("UPDATE " + quoteTable(qb, qb.updateClause.Table))
+		return buildClauses(qb, b, args, buildTableList, buildJoins, buildSet, buildWhere, buildReturning)
+	case d.Supports(FeatureUpdateFrom):
+		src, err := qb.joinsAsFrom()
+		if err != nil {
+			return nil, err
 		}
-		args = buildWhere(qb, &sqlBuilder, args)
+		return buildClauses(src, b, args, buildUpdate, buildOutput("INSERTED"), buildFrom, buildJoins, buildWhere, buildReturning)
+	}
+	return nil, fmt.Errorf("multi-table updates are not supported by the %s dialect", d.Name())
+}
 
-	case qb.selectClause != nil:
-		if len(qb.selectClause.Columns) == 0 {
-			return "", nil, errors.New("select clause requires columns")
+func (qb *QueryBuilder) buildDeleteStatement(b *strings.Builder, args []any) ([]any, error) {
+	if qb.deleteClause.Table == "" {
+		return nil, errors.New("delete requires a table name")
+	}
+	if len(qb.whereClause) == 0 {
+		return nil, errors.New("delete requires where condition")
+	}
+
+	if qb.fromClause == nil && len(qb.joinClause) == 0 {
+		args = buildDelete(qb, b, args)
+		return buildClauses(qb, b, args, buildOutput("DELETED"), buildWhere, buildReturning)
+	}
+
+	d := qb.getDialect()
+	switch {
+	case d.Supports(FeatureDeleteJoin):
+		table := qb.deleteClause.Table
+		b.WriteString("DELETE " + quoteTable(qb, tableRef(table)))
+		args, err := buildOutput("DELETED")(qb, b, args)
+		if err != nil {
+			return nil, err
 		}
-		if qb.fromClause == nil || qb.fromClause.Table == "" {
-			return "", nil, errors.New("from clause is required for select")
+		b.WriteString(" FROM " + quoteTable(qb, table))
+		return buildClauses(qb, b, args, buildTableList, buildJoins, buildWhere, buildReturning)
+	case d.Supports(FeatureDeleteUsing):
+		src, err := qb.joinsAsFrom()
+		if err != nil {
+			return nil, err
 		}
-		buildSelect(qb, &sqlBuilder)
-		buildFrom(qb, &sqlBuilder)
-		args = buildJoins(qb, &sqlBuilder, args)
-		args = buildWhere(qb, &sqlBuilder, args)
-		args = buildGroupBy(qb, &sqlBuilder, args)
-		args = buildHaving(qb, &sqlBuilder, args)
-		args = buildOrderBy(qb, &sqlBuilder, args)
-		args = buildLimt(qb, &sqlBuilder, args)
-		args = buildOffset(qb, &sqlBuilder, args)
+		args = buildDelete(src, b, args)
+		return buildClauses(src, b, args, buildUsing, buildJoins, buildWhere, buildReturning)
+	}
+	return nil, fmt.Errorf("multi-table deletes are not supported by the %s dialect", d.Name())
+}
 
-	default:
-		return "", nil, errors.New("no query type specified (select/insert/update)")
+// joinsAsFrom prepares a multi-table update or delete for dialects that take
+// the other tables as a list, as in Postgres' UPDATE ... FROM and
+// DELETE ... USING. Without a From table, the first join becomes the from
+// item and its condition moves to the where clause.
+func (qb *QueryBuilder) joinsAsFrom() (*QueryBuilder, error) {
+	if qb.fromClause != nil {
+		return qb, nil
 	}
 
-	return sqlBuilder.String(), args, nil
+	j := qb.joinClause[0]
+	if (j.Type != clauses.InnerJoin && j.Type != clauses.CrossJoin) || j.Lateral || len(j.Using) > 0 {
+		return nil, fmt.Errorf("multi-table statements on %s need a From table or a plain inner join first, got %s",
+			qb.getDialect().Name(), j.Type)
+	}
+
+	src := *qb
+	src.fromClause = &clauses.From{Table: j.Table, Alias: j.Alias, Args: j.TableArgs}
+	src.joinClause = qb.joinClause[1:]
+	if j.Condition != "" {
+		src.whereClause = append([]*clauses.Where{clauses.NewWhere(j.Condition, j.Args)}, qb.whereClause...)
+	}
+	return &src, nil
+}
+
+func (qb *QueryBuilder) buildSelectStatement(b *strings.Builder, args []any) ([]any, error) {
+	if qb.returningClause != nil {
+		return nil, errors.New("returning requires an insert, update or delete")
+	}
+
+	args, err := qb.buildSelectBody(b, args)
+	if err != nil {
+		return nil, err
+	}
+	return buildClauses(qb, b, args, infallible(buildOrderBy), buildPagination)
+}
+
+// buildSelectBody renders the select statement up to, but excluding, its
+// order by and pagination, including any set operations.
+func (qb *QueryBuilder) buildSelectBody(b *strings.Builder, args []any) ([]any, error) {
+	if qb.selectClause == nil || len(qb.selectClause.Columns) == 0 {
+		return nil, errors.New("select clause requires columns")
+	}
+	if qb.fromClause == nil || qb.fromClause.Table == "" {
+		return nil, errors.New("from clause is required for select")
+	}
+
+	return buildClauses(qb, b, args,
+		buildSelect,
+		buildFrom,
+		buildJoins,
+		buildWhere,
+		buildGroupBy,
+		buildHaving,
+		infallible(buildWindows),
+		buildSetOperations,
+	)
+}
+
+// needsParensInCompound reports whether qb must be parenthesized when it is
+// a branch of a set operation.
+func (qb *QueryBuilder) needsParensInCompound() bool {
+	return qb.hasOrderBy() || qb.limitClause != nil || qb.offsetClause != nil ||
+		len(qb.setOperations) > 0 || len(qb.withClause) > 0
+}
+
+// selectsDistinct reports whether qb is a SELECT DISTINCT, set with
+// Select("DISTINCT id", ...).
+func (qb *QueryBuilder) selectsDistinct() bool {
+	if qb.selectClause == nil || len(qb.selectClause.Columns) == 0 {
+		return false
+	}
+	first := strings.TrimSpace(qb.selectClause.Columns[0])
+	return len(first) > len("DISTINCT") && strings.EqualFold(first[:len("DISTINCT")], "DISTINCT") &&
+		(first[len("DISTINCT")] == ' ' || first[len("DISTINCT")] == '(')
+}
+
+// selectColumnCount returns the number of columns qb selects. It reports
+// false when the count cannot be known from the builder, as with "*" or
+// "t.*".
+func (qb *QueryBuilder) selectColumnCount() (int, bool) {
+	if qb.selectClause == nil {
+		return 0, false
+	}
+
+	n := 0
+	for _, col := range qb.selectClause.Columns {
+		depth := 0
+		var quote byte
+		for i := 0; i < len(col); i++ {
+			c := col[i]
+			switch {
+			case quote != 0:
+				if c == quote {
+					quote = 0
+				}
+			case c == '\'' || c == '"' || c == '`':
+				quote = c
+			case c == '(':
+				depth++
+			case c == ')':
+				depth--
+			case c == '*' && depth == 0:
+				return 0, false
+			case c == ',' && depth == 0:
+				n++
+			}
+		}
+		n++
+	}
+	return n, true
+}
+
+// hasOrderBy reports whether the query is ordered, by OrderBy or Seek.
+func (qb *QueryBuilder) hasOrderBy() bool {
+	return qb.orderByClause != nil || len(qb.seekKeys) > 0
+}
+
+// buildSubquery renders sub with the dialect and identifier quoting of qb,
+// leaving placeholders as "?" so they are numbered with the parent statement.
+func (qb *QueryBuilder) buildSubquery(sub *QueryBuilder) (string, []any, error) {
+	nested := *sub
+	nested.dialect = qb.dialect
+	nested.quoteIdents = qb.quoteIdents || sub.quoteIdents
+	return nested.build()
+}
+
+// usesTop reports whether the limit is rendered as SELECT TOP instead of
+// after the order by clause. Compound queries never use TOP since it would
+// only apply to their first branch.
+func (qb *QueryBuilder) usesTop() bool {
+	return qb.limitClause != nil && qb.offsetClause == nil && len(qb.setOperations) == 0 &&
+		qb.getDialect().Pagination() == TopOffsetFetch
+}
+
+func (qb *QueryBuilder) getDialect() Dialect {
+	if qb.dialect == nil {
+		return Default
+	}
+	return qb.dialect
 }
 
 func (qb *QueryBuilder) cloneForCount() *QueryBuilder {
 	return &QueryBuilder{
+		withClause:    qb.withClause,
+		selectClause:  qb.selectClause,
+		setOperations: qb.setOperations,
 		fromClause:    qb.fromClause,
 		whereClause:   slices.Clone(qb.whereClause),
 		joinClause:    slices.Clone(qb.joinClause),
 		groupByClause: qb.groupByClause,
+		havingClause:  slices.Clone(qb.havingClause),
+		windows:       qb.windows,
+		dialect:       qb.dialect,
+		quoteIdents:   qb.quoteIdents,
+		err:           qb.err,
 	}
 }
+
+// fail records the first error raised while configuring the builder. Build
+// returns it instead of rendering the query.
+func (qb *QueryBuilder) fail(err error) *QueryBuilder {
+	if qb.err == nil {
+		qb.err = err
+	}
+	return qb
+}
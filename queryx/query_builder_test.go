@@ -142,7 +142,7 @@ func TestQueryBuilder_Build_CountTotalWithGroupBy(t *testing.T) {
 		Where("users.active = ?", []any{true}).
 		GroupBy("users.id")
 
-	expectedExpr := "SELECT COUNT(*) FROM (SELECT 1 FROM users INNER JOIN orders ON users.id = orders.user_id WHERE users.active = ?) AS subquery"
+	expectedExpr := "SELECT COUNT(*) FROM (SELECT 1 FROM users INNER JOIN orders ON users.id = orders.user_id WHERE users.active = ? GROUP BY users.id) AS subquery"
 	expectedArgs := []any{true}
 
 	sql, args, _ := qb.CountTotal().Build()
@@ -156,6 +156,28 @@ func TestQueryBuilder_Build_CountTotalWithGroupBy(t *testing.T) {
 
 }
 
+func TestQueryBuilder_Build_CountTotalWithHaving(t *testing.T) {
+	qb := NewQuery().
+		Select("users.name", "orders.total").
+		From("users").
+		Join("orders", "users.id = orders.user_id", nil).
+		Where("users.active = ?", []any{true}).
+		GroupBy("users.id").
+		Having("SUM(orders.total) > ?", []any{100})
+
+	expectedExpr := "SELECT COUNT(*) FROM (SELECT 1 FROM users INNER JOIN orders ON users.id = orders.user_id WHERE users.active = ? GROUP BY users.id HAVING SUM(orders.total) > ?) AS subquery"
+	expectedArgs := []any{true, 100}
+
+	sql, args, _ := qb.CountTotal().Build()
+
+	if sql != expectedExpr {
+		t.Errorf("expected SQL:\n%s\ngot:\n%s", expectedExpr, sql)
+	}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("expected args: %v, got: %v", expectedArgs, args)
+	}
+}
+
 func TestQueryBuilder_Build_Join(t *testing.T) {
 	qb := NewQuery().
 		Select("id", "name").
@@ -297,3 +319,41 @@ func TestQueryBuilder_Build_Having(t *testing.T) {
 		t.Errorf("expected args: %v, got: %v", expectedArgs, args)
 	}
 }
+
+func TestQueryBuilder_Build_WhereSliceExpansion(t *testing.T) {
+	qb := NewQuery().
+		WithDialect(Postgres).
+		Select("id").
+		From("users").
+		Join("orders", "orders.user_id = users.id AND orders.status IN (?)", []any{[]string{"paid", "shipped"}}).
+		Where("users.id IN (?) AND users.active = ?", []any{[]int{1, 2, 3}, true}).
+		Where("users.avatar <> ?", []any{[]byte("x")}).
+		GroupBy("users.id").
+		Having("COUNT(*) IN (?)", []any{[]int64{1, 2}})
+
+	sql, args, err := qb.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedExpr := "SELECT id FROM users INNER JOIN orders ON orders.user_id = users.id AND orders.status IN ($1, $2) WHERE users.id IN ($3, $4, $5) AND users.active = $6 AND users.avatar <> $7 GROUP BY users.id HAVING COUNT(*) IN ($8, $9)"
+	expectedArgs := []any{"paid", "shipped", 1, 2, 3, true, []byte("x"), int64(1), int64(2)}
+
+	if sql != expectedExpr {
+		t.Errorf("expected SQL:\n%s\ngot:\n%s", expectedExpr, sql)
+	}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("expected args: %v, got: %v", expectedArgs, args)
+	}
+}
+
+func TestQueryBuilder_Build_WhereEmptySlice(t *testing.T) {
+	qb := NewQuery().
+		Select("id").
+		From("users").
+		Where("id IN (?)", []any{[]int{}})
+
+	if _, _, err := qb.Build(); err == nil {
+		t.Fatal("expected error for empty slice")
+	}
+}
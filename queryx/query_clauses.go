@@ -1,74 +1,257 @@
 package queryx
 
 import (
+	"database/sql/driver"
+	"errors"
 	"fmt"
+	"reflect"
+	"slices"
 	"strings"
+
+	"github.com/MattConce/goqueryx/queryx/clauses"
 )
 
-func buildSelect(qb *QueryBuilder, b *strings.Builder) {
+// buildFunc renders one clause of a statement.
+type buildFunc func(qb *QueryBuilder, b *strings.Builder, args []any) ([]any, error)
+
+// buildClauses renders each clause in order, stopping at the first error.
+func buildClauses(qb *QueryBuilder, b *strings.Builder, args []any, fns ...buildFunc) ([]any, error) {
+	var err error
+	for _, fn := range fns {
+		if args, err = fn(qb, b, args); err != nil {
+			return nil, err
+		}
+	}
+	return args, nil
+}
+
+// infallible adapts a clause builder that cannot fail to a buildFunc.
+func infallible(fn func(qb *QueryBuilder, b *strings.Builder, args []any) []any) buildFunc {
+	return func(qb *QueryBuilder, b *strings.Builder, args []any) ([]any, error) {
+		return fn(qb, b, args), nil
+	}
+}
+
+func buildWith(qb *QueryBuilder, b *strings.Builder, args []any) ([]any, error) {
+	if len(qb.withClause) == 0 {
+		return args, nil
+	}
+
+	b.WriteString("WITH ")
+	for _, w := range qb.withClause {
+		if w.Recursive && qb.getDialect().Supports(FeatureRecursiveKeyword) {
+			b.WriteString("RECURSIVE ")
+			break
+		}
+	}
+
+	var err error
+	for i, w := range qb.withClause {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(quoteTable(qb, w.Name))
+		if len(w.Columns) > 0 {
+			b.WriteString(" (" + strings.Join(quoteColumns(qb, w.Columns), ", ") + ")")
+		}
+		b.WriteString(" AS (")
+		if args, err = appendExpr(qb, b, w.Query, w.Args, args); err != nil {
+			return nil, err
+		}
+		b.WriteString(")")
+	}
+	b.WriteString(" ")
+	return args, nil
+}
+
+func buildSelect(qb *QueryBuilder, b *strings.Builder, args []any) ([]any, error) {
 	b.WriteString("SELECT ")
-	b.WriteString(strings.Join(qb.selectClause.Columns, ", "))
+	columns := qb.selectClause.Columns
+	if qb.usesTop() {
+		// T-SQL wants "SELECT DISTINCT TOP (n) ...".
+		if qb.selectsDistinct() {
+			columns = slices.Clone(columns)
+			columns[0] = strings.TrimSpace(strings.TrimSpace(columns[0])[len("DISTINCT"):])
+			b.WriteString("DISTINCT ")
+		}
+		b.WriteString("TOP (?) ")
+		args = append(args, qb.limitClause.Limit)
+	}
+	return appendExpr(qb, b, strings.Join(quoteColumns(qb, columns), ", "), qb.selectClause.Args, args)
 }
 
-func buildFrom(qb *QueryBuilder, b *strings.Builder) {
+func buildFrom(qb *QueryBuilder, b *strings.Builder, args []any) ([]any, error) {
 	b.WriteString(" FROM ")
-	b.WriteString(qb.fromClause.Table)
+	from := qb.fromClause
+	return appendTable(qb, b, from.Table, from.Alias, from.Args, args)
+}
+
+// buildTableList writes the from table as another entry of a comma
+// separated table list, as in MySQL's "UPDATE a, b SET ...".
+func buildTableList(qb *QueryBuilder, b *strings.Builder, args []any) ([]any, error) {
+	if qb.fromClause == nil {
+		return args, nil
+	}
+	b.WriteString(", ")
+	from := qb.fromClause
+	return appendTable(qb, b, from.Table, from.Alias, from.Args, args)
 }
 
-func buildWhere(qb *QueryBuilder, b *strings.Builder, args []any) []any {
+func buildUsing(qb *QueryBuilder, b *strings.Builder, args []any) ([]any, error) {
+	b.WriteString(" USING ")
+	from := qb.fromClause
+	return appendTable(qb, b, from.Table, from.Alias, from.Args, args)
+}
+
+func buildWhere(qb *QueryBuilder, b *strings.Builder, args []any) ([]any, error) {
 	if len(qb.whereClause) == 0 {
-		return args
+		return args, nil
 	}
 
-	conditions := make([]string, 0, len(qb.whereClause))
-	for _, w := range qb.whereClause {
-		conditions = append(conditions, w.Condition)
-		args = append(args, w.Args...)
+	op := ""
+	if len(qb.whereClause) > 1 {
+		op = opAnd
 	}
 
+	var err error
 	b.WriteString(" WHERE ")
-	b.WriteString(strings.Join(conditions, " AND "))
-	return args
+	for i, w := range qb.whereClause {
+		if i > 0 {
+			b.WriteString(" AND ")
+		}
+		if args, err = appendCondition(qb, b, w.Condition, w.Args, op, args); err != nil {
+			return nil, err
+		}
+	}
+	return args, nil
 }
 
-func buildJoins(qb *QueryBuilder, b *strings.Builder, args []any) []any {
+func buildJoins(qb *QueryBuilder, b *strings.Builder, args []any) ([]any, error) {
 	if len(qb.joinClause) == 0 {
-		return args
+		return args, nil
 	}
 
-	joinClauses := make([]string, 0, len(qb.joinClause))
+	var err error
 	for _, j := range qb.joinClause {
-		joinClauses = append(joinClauses, fmt.Sprintf("%s %s ON %s", j.Type, j.Table, j.Condition))
-		args = append(args, j.Args...)
+		if err = checkJoinSupport(qb.getDialect(), j); err != nil {
+			return nil, err
+		}
+
+		b.WriteString(" " + j.Type + " ")
+		if j.Lateral {
+			b.WriteString("LATERAL ")
+		}
+		if args, err = appendTable(qb, b, j.Table, j.Alias, j.TableArgs, args); err != nil {
+			return nil, err
+		}
+
+		switch {
+		case len(j.Using) > 0:
+			b.WriteString(" USING (" + strings.Join(quoteColumns(qb, j.Using), ", ") + ")")
+		case j.Type == clauses.CrossJoin:
+		case j.Condition == "" && j.Lateral:
+			b.WriteString(" ON 1=1")
+		default:
+			b.WriteString(" ON ")
+			if args, err = appendCondition(qb, b, j.Condition, j.Args, "", args); err != nil {
+				return nil, err
+			}
+		}
 	}
+	return args, nil
+}
 
-	b.WriteString(" ")
-	b.WriteString(strings.Join(joinClauses, " "))
-	return args
+// checkJoinSupport rejects join forms the dialect cannot execute.
+func checkJoinSupport(d Dialect, j *clauses.Join) error {
+	switch {
+	case j.Type == clauses.FullJoin && !d.Supports(FeatureFullJoin):
+		return fmt.Errorf("%s is not supported by the %s dialect", j.Type, d.Name())
+	case j.Lateral && !d.Supports(FeatureLateralJoin):
+		return fmt.Errorf("LATERAL joins are not supported by the %s dialect", d.Name())
+	case len(j.Using) > 0 && !d.Supports(FeatureJoinUsing):
+		return fmt.Errorf("JOIN ... USING is not supported by the %s dialect", d.Name())
+	}
+	return nil
 }
 
-func buildGroupBy(qb *QueryBuilder, b *strings.Builder, args []any) []any {
-	if qb.groupByClause != nil {
-		b.WriteString(" GROUP BY ")
-		b.WriteString(strings.Join(qb.groupByClause.Columns, ", "))
+func buildGroupBy(qb *QueryBuilder, b *strings.Builder, args []any) ([]any, error) {
+	g := qb.groupByClause
+	if g == nil {
+		return args, nil
 	}
-	return args
+
+	d := qb.getDialect()
+	columns := strings.Join(quoteColumns(qb, g.Columns), ", ")
+	switch {
+	case g.Type == "":
+		b.WriteString(" GROUP BY " + columns)
+	case g.Type == clauses.Rollup && d.Supports(FeatureWithRollup):
+		b.WriteString(" GROUP BY " + columns + " WITH ROLLUP")
+	case !d.Supports(FeatureGroupingSets):
+		return nil, fmt.Errorf("GROUP BY %s is not supported by the %s dialect", g.Type, d.Name())
+	case g.Type == clauses.GroupingSets:
+		sets := make([]string, len(g.Sets))
+		for i, set := range g.Sets {
+			sets[i] = "(" + strings.Join(quoteColumns(qb, set), ", ") + ")"
+		}
+		b.WriteString(" GROUP BY GROUPING SETS (" + strings.Join(sets, ", ") + ")")
+	default:
+		b.WriteString(" GROUP BY " + g.Type + " (" + columns + ")")
+	}
+	return args, nil
 }
 
-func buildHaving(qb *QueryBuilder, b *strings.Builder, args []any) []any {
+func buildHaving(qb *QueryBuilder, b *strings.Builder, args []any) ([]any, error) {
 	if len(qb.havingClause) == 0 {
-		return args
+		return args, nil
 	}
 
-	conditions := make([]string, 0, len(qb.havingClause))
-	for _, w := range qb.havingClause {
-		conditions = append(conditions, w.Condition)
-		args = append(args, w.Args...)
+	op := ""
+	if len(qb.havingClause) > 1 {
+		op = opAnd
 	}
 
+	var err error
 	b.WriteString(" HAVING ")
-	b.WriteString(strings.Join(conditions, " AND "))
-	return args
+	for i, h := range qb.havingClause {
+		if i > 0 {
+			b.WriteString(" AND ")
+		}
+		if args, err = appendCondition(qb, b, h.Condition, h.Args, op, args); err != nil {
+			return nil, err
+		}
+	}
+	return args, nil
+}
+
+func buildSetOperations(qb *QueryBuilder, b *strings.Builder, args []any) ([]any, error) {
+	var err error
+	for _, op := range qb.setOperations {
+		sub, ok := op.Query.(*QueryBuilder)
+		if !ok || sub == nil {
+			return nil, fmt.Errorf("%s requires a query", strings.ToLower(op.Op))
+		}
+
+		b.WriteString(" " + op.Op + " ")
+		if !sub.needsParensInCompound() {
+			if args, err = appendSubquery(qb, b, sub, args); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		d := qb.getDialect()
+		if !d.Supports(FeatureCompoundParens) {
+			return nil, fmt.Errorf("%s does not allow order by, limit or nested set operations in a %s branch",
+				d.Name(), strings.ToLower(op.Op))
+		}
+		b.WriteString("(")
+		if args, err = appendSubquery(qb, b, sub, args); err != nil {
+			return nil, err
+		}
+		b.WriteString(")")
+	}
+	return args, nil
 }
 
 func buildLimt(qb *QueryBuilder, b *strings.Builder, args []any) []any {
@@ -87,10 +270,51 @@ func buildOffset(qb *QueryBuilder, b *strings.Builder, args []any) []any {
 	return args
 }
 
+func buildPagination(qb *QueryBuilder, b *strings.Builder, args []any) ([]any, error) {
+	if qb.limitClause == nil && qb.offsetClause == nil {
+		return args, nil
+	}
+
+	d := qb.getDialect()
+	switch d.Pagination() {
+	case OffsetFetch, TopOffsetFetch:
+		if qb.usesTop() {
+			return args, nil
+		}
+		if qb.offsetClause == nil && d.Pagination() == OffsetFetch {
+			b.WriteString(" FETCH FIRST ? ROWS ONLY")
+			return append(args, qb.limitClause.Limit), nil
+		}
+		if !qb.hasOrderBy() {
+			return nil, fmt.Errorf("offset on %s requires an order by clause", d.Name())
+		}
+		if qb.offsetClause != nil {
+			b.WriteString(" OFFSET ? ROWS")
+			args = append(args, qb.offsetClause.Offset)
+		} else {
+			b.WriteString(" OFFSET 0 ROWS")
+		}
+		if qb.limitClause != nil {
+			b.WriteString(" FETCH NEXT ? ROWS ONLY")
+			args = append(args, qb.limitClause.Limit)
+		}
+		return args, nil
+	default:
+		args = buildLimt(qb, b, args)
+		return buildOffset(qb, b, args), nil
+	}
+}
+
 func buildOrderBy(qb *QueryBuilder, b *strings.Builder, args []any) []any {
+	terms := sortKeyTerms(qb)
 	if qb.orderByClause != nil {
+		for _, term := range qb.orderByClause.Columns {
+			terms = append(terms, quoteOrderBy(qb, term))
+		}
+	}
+	if len(terms) > 0 {
 		b.WriteString(" ORDER BY ")
-		b.WriteString(strings.Join(qb.orderByClause.Columns, ", "))
+		b.WriteString(strings.Join(terms, ", "))
 	}
 	return args
 }
@@ -99,30 +323,34 @@ func buildInsert(qb *QueryBuilder, b *strings.Builder, args []any) []any {
 	if qb.insertClause != nil {
 		clause := qb.insertClause
 		b.WriteString(fmt.Sprintf("INSERT INTO %s (%s)",
-			clause.Table,
-			strings.Join(clause.Columns, ", ")))
+			quoteTable(qb, clause.Table),
+			strings.Join(quoteColumns(qb, clause.Columns), ", ")))
 	}
 	return args
 }
 
-func buildUpdate(qb *QueryBuilder, b *strings.Builder, args []any) []any {
-	if qb.updateClause != nil {
-		clause := qb.updateClause
-
-		b.WriteString(fmt.Sprintf("UPDATE %s SET ", clause.Table))
+func buildUpdate(qb *QueryBuilder, b *strings.Builder, args []any) ([]any, error) {
+	b.WriteString("UPDATE " + quoteTable(qb, qb.updateClause.Table))
+	return buildSet(qb, b, args)
+}
 
-		setClauses := make([]string, len(clause.Columns))
-		for i, col := range clause.Columns {
-			setClauses[i] = fmt.Sprintf("%s = ?", col)
-		}
-		b.WriteString(strings.Join(setClauses, ", "))
+// buildSet writes the SET list of an update: the Update columns paired with
+// the Values arguments, followed by the assignments added with Set, SetExpr,
+// Increment and Decrement.
+func buildSet(qb *QueryBuilder, b *strings.Builder, args []any) ([]any, error) {
+	set := make([]*clauses.Assignment, 0, len(qb.updateClause.Columns)+len(qb.setClause))
+	for i, col := range qb.updateClause.Columns {
+		set = append(set, clauses.NewAssignment(col, "?", []any{bindValue{qb.valuesClause.Args[i]}}))
 	}
-	return args
+	set = append(set, qb.setClause...)
+
+	b.WriteString(" SET ")
+	return appendAssignments(qb, b, set, args)
 }
 
 func buildDelete(qb *QueryBuilder, b *strings.Builder, args []any) []any {
 	if qb.deleteClause != nil {
-		b.WriteString(fmt.Sprintf("DELETE FROM %s", qb.deleteClause.Table))
+		b.WriteString(fmt.Sprintf("DELETE FROM %s", quoteTable(qb, qb.deleteClause.Table)))
 	}
 	return args
 }
@@ -147,3 +375,275 @@ func buildMultiValues(qb *QueryBuilder, b *strings.Builder, args []any) []any {
 	b.WriteString(strings.Join(placeholders, ", "))
 	return args
 }
+
+func buildInsertSelect(qb *QueryBuilder, b *strings.Builder, args []any) ([]any, error) {
+	sub := qb.insertSelectClause.Query.(*QueryBuilder)
+	if n, ok := sub.selectColumnCount(); ok && n != len(qb.insertClause.Columns) {
+		return nil, fmt.Errorf("insert has %d columns but select returns %d", len(qb.insertClause.Columns), n)
+	}
+
+	b.WriteString(" ")
+	return appendSubquery(qb, b, sub, args)
+}
+
+func buildUpsert(qb *QueryBuilder, b *strings.Builder, args []any) ([]any, error) {
+	d := qb.getDialect()
+	var err error
+
+	if c := qb.onConflictClause; c != nil {
+		if !d.Supports(FeatureOnConflict) {
+			return nil, fmt.Errorf("ON CONFLICT is not supported by the %s dialect", d.Name())
+		}
+		if c.DoNothing == (len(c.Set) > 0) {
+			return nil, errors.New("on conflict requires either do nothing or do update")
+		}
+		if len(c.Set) > 0 && len(c.Columns) == 0 {
+			return nil, errors.New("on conflict do update requires conflict columns")
+		}
+
+		b.WriteString(" ON CONFLICT")
+		if len(c.Columns) > 0 {
+			b.WriteString(" (" + strings.Join(quoteColumns(qb, c.Columns), ", ") + ")")
+		}
+		if c.DoNothing {
+			b.WriteString(" DO NOTHING")
+			return args, nil
+		}
+
+		b.WriteString(" DO UPDATE SET ")
+		if args, err = appendAssignments(qb, b, c.Set, args); err != nil {
+			return nil, err
+		}
+		if c.Condition != "" {
+			b.WriteString(" WHERE ")
+			if args, err = appendCondition(qb, b, c.Condition, c.Args, "", args); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if c := qb.onDuplicateKeyClause; c != nil {
+		if !d.Supports(FeatureOnDuplicateKey) {
+			return nil, fmt.Errorf("ON DUPLICATE KEY UPDATE is not supported by the %s dialect", d.Name())
+		}
+		b.WriteString(" ON DUPLICATE KEY UPDATE ")
+		if args, err = appendAssignments(qb, b, c.Set, args); err != nil {
+			return nil, err
+		}
+	}
+	return args, nil
+}
+
+// buildReturning writes the RETURNING clause that ends insert, update and
+// delete statements. SQL Server returns rows through OUTPUT instead, which
+// is written by buildOutput.
+func buildReturning(qb *QueryBuilder, b *strings.Builder, args []any) ([]any, error) {
+	if qb.returningClause == nil {
+		return args, nil
+	}
+
+	d := qb.getDialect()
+	switch {
+	case d.Supports(FeatureReturning):
+		b.WriteString(" RETURNING " + strings.Join(quoteColumns(qb, qb.returningClause.Columns), ", "))
+	case !d.Supports(FeatureOutput):
+		return nil, fmt.Errorf("RETURNING is not supported by the %s dialect", d.Name())
+	}
+	return args, nil
+}
+
+// buildOutput returns a buildFunc writing the SQL Server OUTPUT clause, with
+// columns read from the given pseudo table, INSERTED or DELETED.
+func buildOutput(table string) buildFunc {
+	return func(qb *QueryBuilder, b *strings.Builder, args []any) ([]any, error) {
+		if qb.returningClause == nil || !qb.getDialect().Supports(FeatureOutput) {
+			return args, nil
+		}
+
+		columns := make([]string, len(qb.returningClause.Columns))
+		for i, col := range qb.returningClause.Columns {
+			columns[i] = outputColumn(qb, table, col)
+		}
+		b.WriteString(" OUTPUT " + strings.Join(columns, ", "))
+		return args, nil
+	}
+}
+
+// outputColumn prefixes a plain column, "*" or "col AS alias" with the
+// pseudo table. Expressions are written as given and must name the pseudo
+// table themselves.
+func outputColumn(qb *QueryBuilder, table, column string) string {
+	fields := strings.Fields(column)
+	switch {
+	case column == "*":
+		return table + ".*"
+	case len(fields) == 1 && identPattern.MatchString(fields[0]),
+		len(fields) == 3 && identPattern.MatchString(fields[0]) && strings.EqualFold(fields[1], "AS"):
+		return table + "." + quoteColumn(qb, column)
+	}
+	return column
+}
+
+// appendAssignments writes a comma separated list of "column = expr".
+func appendAssignments(qb *QueryBuilder, b *strings.Builder, set []*clauses.Assignment, args []any) ([]any, error) {
+	var err error
+	for i, a := range set {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(quoteColumn(qb, a.Column) + " = ")
+		if args, err = appendExpr(qb, b, a.Expr, a.Args, args); err != nil {
+			return nil, err
+		}
+	}
+	return args, nil
+}
+
+// appendTable writes a table reference, which may be a derived table built
+// from a subquery argument, followed by its alias.
+func appendTable(qb *QueryBuilder, b *strings.Builder, table, alias string, tableArgs []any, args []any) ([]any, error) {
+	args, err := appendExpr(qb, b, quoteTable(qb, table), tableArgs, args)
+	if err != nil {
+		return nil, err
+	}
+	if alias != "" {
+		b.WriteString(tableAlias(qb, quoteTable(qb, alias)))
+	}
+	return args, nil
+}
+
+// tableAlias returns the alias clause of a table reference: " AS alias", or
+// " alias" on dialects that reject AS there.
+func tableAlias(qb *QueryBuilder, alias string) string {
+	if qb.getDialect().Supports(FeatureTableAliasAs) {
+		return " AS " + alias
+	}
+	return " " + alias
+}
+
+// appendSubquery writes the SQL of sub without surrounding parentheses.
+func appendSubquery(qb *QueryBuilder, b *strings.Builder, sub *QueryBuilder, args []any) ([]any, error) {
+	query, subArgs, err := qb.buildSubquery(sub)
+	if err != nil {
+		return nil, err
+	}
+	b.WriteString(query)
+	return append(args, subArgs...), nil
+}
+
+// appendCondition writes a where, having or join condition. A condition made
+// of a single Cond placeholder, as added by WhereCond, is rendered as an
+// operand of op so it only gets parentheses when precedence requires them.
+func appendCondition(qb *QueryBuilder, b *strings.Builder, condition string, condArgs []any, op string, args []any) ([]any, error) {
+	if condition == "?" && len(condArgs) == 1 {
+		if c, ok := condArgs[0].(Cond); ok {
+			return appendCond(qb, b, c, op, args)
+		}
+	}
+	return appendExpr(qb, b, condition, condArgs, args)
+}
+
+// appendExpr writes a SQL fragment with "?" placeholders. Arguments that are
+// a Cond, an Expr or a *QueryBuilder are rendered in place of their
+// placeholder, the latter without parentheses so "id IN (?)" and
+// "EXISTS (?)" read naturally. Slices expand into one placeholder per
+// element, so "id IN (?)" with []int{1, 2} becomes "id IN (?, ?)". An empty
+// slice is an error because there is no portable way to render it; use In
+// or NotIn, which render 1=0 and 1=1 instead. All other arguments are kept
+// as bind values.
+func appendExpr(qb *QueryBuilder, b *strings.Builder, expr string, exprArgs []any, args []any) ([]any, error) {
+	if !needsExpansion(exprArgs) {
+		b.WriteString(expr)
+		return append(args, exprArgs...), nil
+	}
+
+	var err error
+	n := 0
+	var quote byte
+	for i := 0; i < len(expr); i++ {
+		c := expr[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '?':
+			if n >= len(exprArgs) {
+				return nil, fmt.Errorf("not enough args for placeholders in %q", expr)
+			}
+			if args, err = appendExprArg(qb, b, exprArgs[n], args); err != nil {
+				return nil, fmt.Errorf("placeholder %d in %q: %w", n+1, expr, err)
+			}
+			n++
+			continue
+		}
+		b.WriteByte(c)
+	}
+	if n != len(exprArgs) {
+		return nil, fmt.Errorf("too many args for placeholders in %q", expr)
+	}
+	return args, nil
+}
+
+func appendExprArg(qb *QueryBuilder, b *strings.Builder, arg any, args []any) ([]any, error) {
+	switch arg := arg.(type) {
+	case Cond:
+		return appendCond(qb, b, arg, opEmbedded, args)
+	case Expr:
+		return arg.appendSQL(qb, b, args)
+	case *QueryBuilder:
+		return appendSubquery(qb, b, arg, args)
+	}
+
+	if values, ok := sliceArgs(arg); ok {
+		if len(values) == 0 {
+			return nil, errors.New("empty slice cannot be expanded")
+		}
+		b.WriteString(strings.TrimSuffix(strings.Repeat("?, ", len(values)), ", "))
+		return append(args, values...), nil
+	}
+
+	b.WriteByte('?')
+	return append(args, arg), nil
+}
+
+// needsExpansion reports whether any argument must be rendered into the SQL
+// text rather than passed as a single bind value.
+func needsExpansion(args []any) bool {
+	for _, arg := range args {
+		switch arg.(type) {
+		case Expr, *QueryBuilder:
+			return true
+		}
+		if _, ok := sliceArgs(arg); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// sliceArgs returns the elements of v when it is a slice that expands into
+// one placeholder per element. Byte slices and driver.Valuer
+// implementations, such as array types provided by drivers, are passed
+// through as a single value.
+func sliceArgs(v any) ([]any, bool) {
+	if v == nil {
+		return nil, false
+	}
+	if _, ok := v.(driver.Valuer); ok {
+		return nil, false
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice || rv.Type().Elem().Kind() == reflect.Uint8 {
+		return nil, false
+	}
+
+	values := make([]any, rv.Len())
+	for i := range values {
+		values[i] = rv.Index(i).Interface()
+	}
+	return values, true
+}
@@ -15,7 +15,7 @@ func TestBuildSelect(t *testing.T) {
 	}
 
 	var sql strings.Builder
-	buildSelect(qb, &sql)
+	buildSelect(qb, &sql, nil)
 
 	expected := "SELECT id, name"
 	if sql.String() != expected {
@@ -65,17 +65,25 @@ func TestBuildUpdate(t *testing.T) {
 			Table:   "users",
 			Columns: []string{"name", "email"},
 		},
+		valuesClause: &clauses.Values{Args: []any{"Ann", "ann@example.com"}},
+		setClause:    []*clauses.Assignment{{Column: "views", Expr: "views + ?", Args: []any{1}}},
 	}
 
 	var sql strings.Builder
-	var args []any
-	_ = buildUpdate(qb, &sql, args)
+	args, err := buildUpdate(qb, &sql, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
 
-	expectedExpr := "UPDATE users SET name = ?, email = ?"
+	expectedExpr := "UPDATE users SET name = ?, email = ?, views = views + ?"
+	expectedArgs := []any{"Ann", "ann@example.com", 1}
 
 	if sql.String() != expectedExpr {
 		t.Errorf("\nexpected: %q\ngot: %q", expectedExpr, sql.String())
 	}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("expected args: %v, got: %v", expectedArgs, args)
+	}
 }
 
 func TestBuildDelete(t *testing.T) {
@@ -99,7 +107,7 @@ func TestBuildFrom(t *testing.T) {
 	}
 
 	var sql strings.Builder
-	buildFrom(qb, &sql)
+	buildFrom(qb, &sql, nil)
 
 	expected := " FROM users"
 	if sql.String() != expected {
@@ -116,7 +124,10 @@ func TestBuildWhere(t *testing.T) {
 	}
 
 	var sql strings.Builder
-	args := buildWhere(qb, &sql, nil)
+	args, err := buildWhere(qb, &sql, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
 
 	expectedExpr := " WHERE age > ?"
 	expectedArgs := []any{18}
@@ -170,7 +181,9 @@ func TestBuildGroupBy(t *testing.T) {
 	}
 
 	var sql strings.Builder
-	buildGroupBy(qb, &sql, nil)
+	if _, err := buildGroupBy(qb, &sql, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
 
 	expected := " GROUP BY id, name"
 	if sql.String() != expected {
@@ -187,7 +200,10 @@ func TestBuildHaving(t *testing.T) {
 	}
 
 	var sql strings.Builder
-	args := buildHaving(qb, &sql, nil)
+	args, err := buildHaving(qb, &sql, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
 
 	expectedExpr := " HAVING age > ?"
 	expectedArgs := []any{18}
@@ -0,0 +1,142 @@
+package queryx
+
+import (
+	"regexp"
+	"strings"
+)
+
+var identPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_$]*$`)
+
+// quoteTable quotes a table reference such as "users", "public.users",
+// "users u" or "users AS u" when identifier quoting is enabled. Anything
+// that is not a plain identifier is returned unchanged.
+func quoteTable(qb *QueryBuilder, table string) string {
+	if !qb.quoteIdents {
+		return table
+	}
+
+	d := qb.getDialect()
+	fields := strings.Fields(table)
+	switch {
+	case len(fields) == 1:
+		if path, ok := quotePath(d, fields[0], false); ok {
+			return path
+		}
+	case len(fields) == 2:
+		path, ok := quotePath(d, fields[0], false)
+		if ok && identPattern.MatchString(fields[1]) {
+			return path + " " + d.QuoteIdent(fields[1])
+		}
+	case len(fields) == 3 && strings.EqualFold(fields[1], "AS"):
+		path, ok := quotePath(d, fields[0], false)
+		if fields[0] == "?" || fields[0] == "(?)" {
+			// The placeholder of an expression or subquery column added by
+			// SelectExpr or SelectSubquery.
+			path, ok = fields[0], true
+		}
+		if ok && identPattern.MatchString(fields[2]) {
+			return path + " " + fields[1] + " " + d.QuoteIdent(fields[2])
+		}
+	}
+	return table
+}
+
+// tableRef returns the name a table reference such as "users u" or
+// "users AS u" is referred to by in the rest of the statement.
+func tableRef(table string) string {
+	fields := strings.Fields(table)
+	switch {
+	case len(fields) == 2:
+		return fields[1]
+	case len(fields) == 3 && strings.EqualFold(fields[1], "AS"):
+		return fields[2]
+	}
+	return table
+}
+
+// quoteColumn quotes a column reference such as "name", "users.name",
+// "users.*" or "name AS n" when identifier quoting is enabled. Expressions
+// like "COUNT(*)" or "DISTINCT id" are returned unchanged.
+func quoteColumn(qb *QueryBuilder, column string) string {
+	if !qb.quoteIdents {
+		return column
+	}
+
+	d := qb.getDialect()
+	fields := strings.Fields(column)
+	switch {
+	case len(fields) == 1:
+		if path, ok := quotePath(d, fields[0], true); ok {
+			return path
+		}
+	case len(fields) == 3 && strings.EqualFold(fields[1], "AS"):
+		path, ok := quotePath(d, fields[0], false)
+		if fields[0] == "?" || fields[0] == "(?)" {
+			// The placeholder of an expression or subquery column added by
+			// SelectExpr or SelectSubquery.
+			path, ok = fields[0], true
+		}
+		if ok && identPattern.MatchString(fields[2]) {
+			return path + " " + fields[1] + " " + d.QuoteIdent(fields[2])
+		}
+	}
+	return column
+}
+
+func quoteColumns(qb *QueryBuilder, columns []string) []string {
+	if !qb.quoteIdents {
+		return columns
+	}
+
+	quoted := make([]string, len(columns))
+	for i, col := range columns {
+		quoted[i] = quoteColumn(qb, col)
+	}
+	return quoted
+}
+
+// quoteOrderBy quotes the column of an order by term such as
+// "name DESC NULLS LAST", keeping the direction and nulls ordering as written.
+func quoteOrderBy(qb *QueryBuilder, term string) string {
+	if !qb.quoteIdents {
+		return term
+	}
+
+	fields := strings.Fields(term)
+	if len(fields) == 0 {
+		return term
+	}
+	path, ok := quotePath(qb.getDialect(), fields[0], false)
+	if !ok {
+		return term
+	}
+
+	rest := fields[1:]
+	if len(rest) > 0 && (strings.EqualFold(rest[0], "ASC") || strings.EqualFold(rest[0], "DESC")) {
+		rest = rest[1:]
+	}
+	if len(rest) == 2 && strings.EqualFold(rest[0], "NULLS") &&
+		(strings.EqualFold(rest[1], "FIRST") || strings.EqualFold(rest[1], "LAST")) {
+		rest = rest[2:]
+	}
+	if len(rest) != 0 {
+		return term
+	}
+	return strings.Join(append([]string{path}, fields[1:]...), " ")
+}
+
+// quotePath quotes each part of a dotted identifier such as "schema.table"
+// or "table.column". It reports false when s is not a plain identifier path.
+func quotePath(d Dialect, s string, allowStar bool) (string, bool) {
+	parts := strings.Split(s, ".")
+	for i, part := range parts {
+		if part == "*" && allowStar && i == len(parts)-1 && i > 0 {
+			continue
+		}
+		if !identPattern.MatchString(part) {
+			return "", false
+		}
+		parts[i] = d.QuoteIdent(part)
+	}
+	return strings.Join(parts, "."), true
+}
@@ -0,0 +1,123 @@
+package queryx
+
+import (
+	"testing"
+)
+
+func TestQuoteIdentifiers_Select(t *testing.T) {
+	qb := NewQuery().
+		WithDialect(Postgres).
+		QuoteIdentifiers().
+		Select("u.id", "u.userName AS name", "COUNT(*) AS total", "o.*").
+		From("public.users u").
+		Join("orders AS o", "o.user_id = u.id", nil).
+		Where("u.active = ?", []any{true}).
+		GroupBy("u.id", "u.userName").
+		OrderBy("order DESC NULLS LAST", "u.id")
+
+	sql, _, err := qb.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedExpr := `SELECT "u"."id", "u"."userName" AS "name", COUNT(*) AS total, "o".* FROM "public"."users" "u" INNER JOIN "orders" AS "o" ON o.user_id = u.id WHERE u.active = $1 GROUP BY "u"."id", "u"."userName" ORDER BY "order" DESC NULLS LAST, "u"."id"`
+
+	if sql != expectedExpr {
+		t.Errorf("expected SQL:\n%s\ngot:\n%s", expectedExpr, sql)
+	}
+}
+
+func TestQuoteIdentifiers_Insert_MySQL(t *testing.T) {
+	qb := NewQuery().
+		WithDialect(MySQL).
+		QuoteIdentifiers().
+		Insert("user", []string{"name", "order"}).
+		Values("John", 1)
+
+	sql, _, err := qb.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedExpr := "INSERT INTO `user` (`name`, `order`) VALUES (?, ?)"
+	if sql != expectedExpr {
+		t.Errorf("expected SQL:\n%s\ngot:\n%s", expectedExpr, sql)
+	}
+}
+
+func TestQuoteIdentifiers_Update_SQLServer(t *testing.T) {
+	qb := NewQuery().
+		WithDialect(SQLServer).
+		QuoteIdentifiers().
+		Update("dbo.user", []string{"order"}).
+		Values(2).
+		Where("id = ?", []any{1})
+
+	sql, _, err := qb.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedExpr := "UPDATE [dbo].[user] SET [order] = @p1 WHERE id = @p2"
+	if sql != expectedExpr {
+		t.Errorf("expected SQL:\n%s\ngot:\n%s", expectedExpr, sql)
+	}
+}
+
+func TestQuoteIdentifiers_Delete(t *testing.T) {
+	qb := NewQuery().
+		QuoteIdentifiers().
+		Delete("user").
+		Where("id = ?", []any{1})
+
+	sql, _, err := qb.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedExpr := `DELETE FROM "user" WHERE id = ?`
+	if sql != expectedExpr {
+		t.Errorf("expected SQL:\n%s\ngot:\n%s", expectedExpr, sql)
+	}
+}
+
+func TestQuoteIdentifiers_Disabled(t *testing.T) {
+	sql, _, err := NewQuery().WithDialect(Postgres).Select("order").From("user").Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedExpr := "SELECT order FROM user"
+	if sql != expectedExpr {
+		t.Errorf("expected SQL:\n%s\ngot:\n%s", expectedExpr, sql)
+	}
+}
+
+func TestQuoteColumn_LeavesExpressions(t *testing.T) {
+	qb := NewQuery().QuoteIdentifiers()
+
+	for _, col := range []string{"*", "COUNT(id)", "DISTINCT id", "a + b", `"already"`, "lower(name) AS n"} {
+		if got := quoteColumn(qb, col); got != col {
+			t.Errorf("expected %q to be left unchanged, got %q", col, got)
+		}
+	}
+}
+
+func TestQuoteIdent_Escaping(t *testing.T) {
+	cases := []struct {
+		dialect  Dialect
+		expected string
+	}{
+		{Postgres, `"we""ird"`},
+		{MySQL, "`we\"ird`"},
+		{SQLServer, `[we"ird]`},
+	}
+	for _, c := range cases {
+		if got := c.dialect.QuoteIdent(`we"ird`); got != c.expected {
+			t.Errorf("%s: expected %s, got %s", c.dialect.Name(), c.expected, got)
+		}
+	}
+	if got := SQLServer.QuoteIdent("a]b"); got != "[a]]b]" {
+		t.Errorf("expected [a]]b], got %s", got)
+	}
+}
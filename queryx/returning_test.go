@@ -0,0 +1,145 @@
+package queryx
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReturning_Postgres(t *testing.T) {
+	cases := []struct {
+		name         string
+		qb           *QueryBuilder
+		expectedExpr string
+		expectedArgs []any
+	}{
+		{
+			"insert",
+			NewQuery().
+				WithDialect(Postgres).
+				Insert("users", []string{"name"}).
+				MultiValues([][]any{{"Ann"}, {"Bob"}}).
+				Returning("id"),
+			"INSERT INTO users (name) VALUES ($1), ($2) RETURNING id",
+			[]any{"Ann", "Bob"},
+		},
+		{
+			"upsert",
+			NewQuery().
+				WithDialect(Postgres).
+				Insert("users", []string{"email"}).
+				Values("a@b.c").
+				OnConflict("email").
+				DoNothing().
+				Returning("id", "email"),
+			"INSERT INTO users (email) VALUES ($1) ON CONFLICT (email) DO NOTHING RETURNING id, email",
+			[]any{"a@b.c"},
+		},
+		{
+			"update",
+			NewQuery().
+				WithDialect(Postgres).
+				Update("users", []string{"name"}).
+				Values("Ann").
+				Where("id = ?", []any{1}).
+				Returning(),
+			"UPDATE users SET name = $1 WHERE id = $2 RETURNING *",
+			[]any{"Ann", 1},
+		},
+		{
+			"delete",
+			NewQuery().
+				WithDialect(SQLite).
+				QuoteIdentifiers().
+				Delete("users").
+				Where("id = ?", []any{1}).
+				Returning("id", "name AS old_name"),
+			`DELETE FROM "users" WHERE id = ? RETURNING "id", "name" AS "old_name"`,
+			[]any{1},
+		},
+	}
+
+	for _, c := range cases {
+		sql, args, err := c.qb.Build()
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", c.name, err)
+		}
+		if sql != c.expectedExpr {
+			t.Errorf("%s: expected SQL:\n%s\ngot:\n%s", c.name, c.expectedExpr, sql)
+		}
+		if !reflect.DeepEqual(args, c.expectedArgs) {
+			t.Errorf("%s: expected args: %v, got: %v", c.name, c.expectedArgs, args)
+		}
+	}
+}
+
+func TestReturning_SQLServerOutput(t *testing.T) {
+	cases := []struct {
+		name         string
+		qb           *QueryBuilder
+		expectedExpr string
+		expectedArgs []any
+	}{
+		{
+			"insert",
+			NewQuery().
+				WithDialect(SQLServer).
+				Insert("users", []string{"name"}).
+				Values("Ann").
+				Returning("id", "name AS inserted_name"),
+			"INSERT INTO users (name) OUTPUT INSERTED.id, INSERTED.name AS inserted_name VALUES (@p1)",
+			[]any{"Ann"},
+		},
+		{
+			"update",
+			NewQuery().
+				WithDialect(SQLServer).
+				QuoteIdentifiers().
+				Update("users", []string{"name"}).
+				Values("Ann").
+				Where("id = ?", []any{1}).
+				Returning("*"),
+			"UPDATE [users] SET [name] = @p1 OUTPUT INSERTED.* WHERE id = @p2",
+			[]any{"Ann", 1},
+		},
+		{
+			"delete",
+			NewQuery().
+				WithDialect(SQLServer).
+				Delete("users").
+				Where("id = ?", []any{1}).
+				Returning("id", "UPPER(DELETED.name)"),
+			"DELETE FROM users OUTPUT DELETED.id, UPPER(DELETED.name) WHERE id = @p1",
+			[]any{1},
+		},
+	}
+
+	for _, c := range cases {
+		sql, args, err := c.qb.Build()
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", c.name, err)
+		}
+		if sql != c.expectedExpr {
+			t.Errorf("%s: expected SQL:\n%s\ngot:\n%s", c.name, c.expectedExpr, sql)
+		}
+		if !reflect.DeepEqual(args, c.expectedArgs) {
+			t.Errorf("%s: expected args: %v, got: %v", c.name, c.expectedArgs, args)
+		}
+	}
+}
+
+func TestReturning_Errors(t *testing.T) {
+	cases := []struct {
+		name string
+		qb   *QueryBuilder
+	}{
+		{"mysql", NewQuery().WithDialect(MySQL).Delete("users").Where("id = ?", []any{1}).Returning("id")},
+		{"oracle", NewQuery().WithDialect(Oracle).Insert("users", []string{"name"}).Values("Ann").Returning("id")},
+		{"select", NewQuery().Select("id").From("users").Returning("id")},
+	}
+
+	for _, c := range cases {
+		if _, _, err := c.qb.Build(); err == nil {
+			t.Errorf("%s: expected error", c.name)
+		}
+	}
+}
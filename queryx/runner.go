@@ -0,0 +1,79 @@
+package queryx
+
+import (
+	"context"
+	"database/sql"
+)
+
+// Runner executes SQL statements. It is satisfied by *sql.DB, *sql.Tx and
+// *sql.Conn.
+type Runner interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// DialectRunner is a Runner that knows the dialect of its database.
+type DialectRunner interface {
+	Runner
+	Dialect() Dialect
+}
+
+// NewRunner wraps r so that queries executed through it are built with d:
+//
+//	db := queryx.NewRunner(sqlDB, queryx.Postgres)
+//	rows, err := queryx.NewQuery().Select("id").From("users").QueryContext(ctx, db)
+func NewRunner(r Runner, d Dialect) DialectRunner {
+	return dialectRunner{Runner: r, dialect: d}
+}
+
+type dialectRunner struct {
+	Runner
+	dialect Dialect
+}
+
+func (r dialectRunner) Dialect() Dialect {
+	return r.dialect
+}
+
+// buildFor builds qb with the dialect of r when r is a DialectRunner with a
+// dialect, and with the builder's own dialect otherwise. qb itself is left
+// unchanged.
+func (qb *QueryBuilder) buildFor(r Runner) (string, []any, error) {
+	if dr, ok := r.(DialectRunner); ok && dr.Dialect() != nil {
+		withDialect := *qb
+		withDialect.dialect = dr.Dialect()
+		return withDialect.Build()
+	}
+	return qb.Build()
+}
+
+// ExecContext builds the query and executes it with r.
+func (qb *QueryBuilder) ExecContext(ctx context.Context, r Runner) (sql.Result, error) {
+	query, args, err := qb.buildFor(r)
+	if err != nil {
+		return nil, err
+	}
+	return r.ExecContext(ctx, query, args...)
+}
+
+// QueryContext builds the query and runs it with r. The caller must close
+// the returned rows.
+func (qb *QueryBuilder) QueryContext(ctx context.Context, r Runner) (*sql.Rows, error) {
+	query, args, err := qb.buildFor(r)
+	if err != nil {
+		return nil, err
+	}
+	return r.QueryContext(ctx, query, args...)
+}
+
+// QueryRowContext builds the query and runs it with r, expecting at most
+// one row. Build errors are returned directly; query errors are deferred to
+// the Scan of the returned row, as with database/sql.
+func (qb *QueryBuilder) QueryRowContext(ctx context.Context, r Runner) (*sql.Row, error) {
+	query, args, err := qb.buildFor(r)
+	if err != nil {
+		return nil, err
+	}
+	return r.QueryRowContext(ctx, query, args...), nil
+}
@@ -0,0 +1,114 @@
+package queryx
+
+import (
+	"context"
+	"database/sql/driver"
+	"reflect"
+	"testing"
+)
+
+func TestRunner_ExecContext(t *testing.T) {
+	db, fdb := newFakeDB(t, func(string, []driver.Value) (*fakeResult, error) {
+		return &fakeResult{rowsAffected: 2}, nil
+	})
+
+	res, err := NewQuery().
+		Update("users", nil).
+		Set("active", false).
+		Where("id IN (?)", []any{[]int{1, 2}}).
+		ExecContext(context.Background(), NewRunner(db, Postgres))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n, _ := res.RowsAffected(); n != 2 {
+		t.Errorf("expected 2 rows affected, got %d", n)
+	}
+
+	call := fdb.lastCall()
+	expectedExpr := "UPDATE users SET active = $1 WHERE id IN ($2, $3)"
+	expectedArgs := []driver.Value{false, int64(1), int64(2)}
+
+	if call.query != expectedExpr {
+		t.Errorf("expected SQL:\n%s\ngot:\n%s", expectedExpr, call.query)
+	}
+	if !reflect.DeepEqual(call.args, expectedArgs) {
+		t.Errorf("expected args: %v, got: %v", expectedArgs, call.args)
+	}
+}
+
+func TestRunner_QueryContext(t *testing.T) {
+	db, fdb := newFakeDB(t, func(string, []driver.Value) (*fakeResult, error) {
+		return &fakeResult{
+			columns: []string{"id"},
+			rows:    [][]driver.Value{{int64(1)}, {int64(2)}},
+		}, nil
+	})
+
+	qb := NewQuery().Select("id").From("users").Where("active = ?", []any{true})
+	rows, err := qb.QueryContext(context.Background(), NewRunner(db, SQLServer))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			t.Fatalf("scan: %v", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatalf("rows: %v", err)
+	}
+
+	if !reflect.DeepEqual(ids, []int{1, 2}) {
+		t.Errorf("expected ids [1 2], got %v", ids)
+	}
+	if q := fdb.lastCall().query; q != "SELECT id FROM users WHERE active = @p1" {
+		t.Errorf("unexpected query: %s", q)
+	}
+	if qb.dialect != nil {
+		t.Error("expected the builder dialect to be left unchanged")
+	}
+}
+
+func TestRunner_QueryRowContext(t *testing.T) {
+	db, fdb := newFakeDB(t, func(string, []driver.Value) (*fakeResult, error) {
+		return &fakeResult{columns: []string{"name"}, rows: [][]driver.Value{{"Ann"}}}, nil
+	})
+
+	// A plain *sql.DB has no dialect, so the builder's own is used.
+	row, err := NewQuery().
+		WithDialect(Oracle).
+		Select("name").
+		From("users").
+		Where("id = ?", []any{1}).
+		QueryRowContext(context.Background(), db)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var name string
+	if err := row.Scan(&name); err != nil {
+		t.Fatalf("scan: %v", err)
+	}
+	if name != "Ann" {
+		t.Errorf("expected Ann, got %q", name)
+	}
+	if q := fdb.lastCall().query; q != "SELECT name FROM users WHERE id = :1" {
+		t.Errorf("unexpected query: %s", q)
+	}
+}
+
+func TestRunner_BuildError(t *testing.T) {
+	db, fdb := newFakeDB(t, nil)
+
+	if _, err := NewQuery().Select("id").ExecContext(context.Background(), db); err == nil {
+		t.Fatal("expected build error")
+	}
+	if len(fdb.queries()) != 0 {
+		t.Errorf("expected no statements to be sent, got %v", fdb.queries())
+	}
+}
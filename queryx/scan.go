@@ -0,0 +1,283 @@
+package queryx
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"iter"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Option configures the helpers that run a builder and read its rows.
+type Option func(*options)
+
+type options struct {
+	ignoreUnmapped bool
+	skipCount      bool
+	concurrent     bool
+}
+
+func newOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// IgnoreUnmapped discards result columns that have no matching struct
+// field instead of failing.
+func IgnoreUnmapped() Option {
+	return func(o *options) {
+		o.ignoreUnmapped = true
+	}
+}
+
+// All runs qb with r and scans every row into a T. T may be a struct, whose
+// fields are matched to columns by db tag, a map with string keys, or a
+// single-column scalar such as int, string or sql.NullString.
+//
+// A column such as "users.name" matches a field tagged "users.name", a
+// "name" field of a nested struct tagged "users", or, failing those, a field
+// tagged "name". Columns that match no field are an error unless
+// IgnoreUnmapped is given.
+func All[T any](ctx context.Context, r Runner, qb *QueryBuilder, opts ...Option) ([]T, error) {
+	rows, err := qb.QueryContext(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	s, err := newRowScanner(reflect.TypeFor[T](), rows, newOptions(opts))
+	if err != nil {
+		return nil, err
+	}
+
+	items := []T{}
+	for rows.Next() {
+		var item T
+		if err := s.scan(rows, reflect.ValueOf(&item).Elem()); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// One runs qb with r and scans its first row into a T, as All does. It
+// returns sql.ErrNoRows when the query returns no rows.
+func One[T any](ctx context.Context, r Runner, qb *QueryBuilder, opts ...Option) (T, error) {
+	var item T
+
+	rows, err := qb.QueryContext(ctx, r)
+	if err != nil {
+		return item, err
+	}
+	defer rows.Close()
+
+	s, err := newRowScanner(reflect.TypeFor[T](), rows, newOptions(opts))
+	if err != nil {
+		return item, err
+	}
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return item, err
+		}
+		return item, sql.ErrNoRows
+	}
+	if err := s.scan(rows, reflect.ValueOf(&item).Elem()); err != nil {
+		return item, err
+	}
+	return item, rows.Close()
+}
+
+// Iter runs qb with r when the loop starts and scans rows one at a time, as
+// All does, without holding the whole result in memory. Rows are closed
+// when the loop ends, including on break. An error is yielded once, with
+// the zero T, and ends the iteration:
+//
+//	for user, err := range queryx.Iter[User](ctx, db, qb) {
+//	    if err != nil {
+//	        return err
+//	    }
+//	    ...
+//	}
+func Iter[T any](ctx context.Context, r Runner, qb *QueryBuilder, opts ...Option) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+
+		rows, err := qb.QueryContext(ctx, r)
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+		defer rows.Close()
+
+		s, err := newRowScanner(reflect.TypeFor[T](), rows, newOptions(opts))
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+
+		for rows.Next() {
+			var item T
+			if err := s.scan(rows, reflect.ValueOf(&item).Elem()); err != nil {
+				yield(zero, err)
+				return
+			}
+			if !yield(item, nil) {
+				return
+			}
+		}
+		if err := rows.Err(); err != nil {
+			yield(zero, err)
+		}
+	}
+}
+
+// Kinds of destination a rowScanner fills.
+const (
+	scanScalar = iota
+	scanStruct
+	scanMap
+)
+
+// rowScanner scans the rows of one result set into values of a type.
+type rowScanner struct {
+	kind    int
+	columns []string
+	// indexes holds the struct field of each column, nil when the column is
+	// discarded.
+	indexes [][]int
+}
+
+func newRowScanner(t reflect.Type, rows *sql.Rows, o options) (*rowScanner, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	s := &rowScanner{columns: columns}
+
+	switch {
+	case t.Kind() == reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("cannot scan into %s: map keys must be strings", t)
+		}
+		s.kind = scanMap
+	case t.Kind() == reflect.Struct && !isScannable(t):
+		s.kind = scanStruct
+		fields := scanFields(t)
+		s.indexes = make([][]int, len(columns))
+		for i, col := range columns {
+			index, ok := matchColumn(fields, col)
+			if !ok && !o.ignoreUnmapped {
+				return nil, fmt.Errorf("column %q has no matching field in %s", col, t)
+			}
+			s.indexes[i] = index
+		}
+	default:
+		if len(columns) != 1 {
+			return nil, fmt.Errorf("cannot scan %d columns into %s", len(columns), t)
+		}
+		s.kind = scanScalar
+	}
+	return s, nil
+}
+
+// scan reads the current row into dest, an addressable value.
+func (s *rowScanner) scan(rows *sql.Rows, dest reflect.Value) error {
+	switch s.kind {
+	case scanScalar:
+		return rows.Scan(dest.Addr().Interface())
+	case scanMap:
+		elem := dest.Type().Elem()
+		targets := make([]any, len(s.columns))
+		for i := range targets {
+			targets[i] = reflect.New(elem).Interface()
+		}
+		if err := rows.Scan(targets...); err != nil {
+			return err
+		}
+		m := reflect.MakeMapWithSize(dest.Type(), len(s.columns))
+		for i, col := range s.columns {
+			m.SetMapIndex(reflect.ValueOf(col).Convert(dest.Type().Key()), reflect.ValueOf(targets[i]).Elem())
+		}
+		dest.Set(m)
+		return nil
+	}
+
+	targets := make([]any, len(s.columns))
+	for i, index := range s.indexes {
+		if index == nil {
+			targets[i] = new(any)
+			continue
+		}
+		targets[i] = allocField(dest, index).Addr().Interface()
+	}
+	return rows.Scan(targets...)
+}
+
+// allocField returns the field at index, allocating nil struct pointers on
+// the way.
+func allocField(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+var (
+	scannerType = reflect.TypeFor[sql.Scanner]()
+	timeType    = reflect.TypeFor[time.Time]()
+)
+
+// isScannable reports whether database/sql can scan a single column into t.
+func isScannable(t reflect.Type) bool {
+	return t == timeType || reflect.PointerTo(t).Implements(scannerType)
+}
+
+// scanFieldsCache holds the map[string][]int of each struct type.
+var scanFieldsCache sync.Map
+
+// scanFields maps the column names of t, a struct type, to field indexes.
+// Names follow dbFields, and fields of a nested struct tagged "prefix" are
+// named "prefix.column".
+func scanFields(t reflect.Type) map[string][]int {
+	if cached, ok := scanFieldsCache.Load(t); ok {
+		return cached.(map[string][]int)
+	}
+
+	fields := map[string][]int{}
+	for _, f := range dbFields(t, true) {
+		fields[f.name] = f.index
+	}
+	cached, _ := scanFieldsCache.LoadOrStore(t, fields)
+	return cached.(map[string][]int)
+}
+
+// matchColumn finds the field of a result column, falling back to a
+// lowercase match and to the column without its table prefix.
+func matchColumn(fields map[string][]int, column string) ([]int, bool) {
+	if index, ok := fields[column]; ok {
+		return index, true
+	}
+	if index, ok := fields[strings.ToLower(column)]; ok {
+		return index, true
+	}
+	if i := strings.LastIndexByte(column, '.'); i >= 0 {
+		return matchColumn(fields, column[i+1:])
+	}
+	return nil, false
+}
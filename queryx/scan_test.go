@@ -0,0 +1,148 @@
+package queryx
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type ScanAudit struct {
+	CreatedAt time.Time `db:"created_at"`
+}
+
+type scanTeam struct {
+	ID   int    `db:"id"`
+	Name string `db:"name"`
+}
+
+type scanUser struct {
+	ID    int            `db:"id"`
+	Name  string         `db:"name"`
+	Email sql.NullString `db:"email"`
+	Team  *scanTeam      `db:"team"`
+	*ScanAudit
+}
+
+func rowsOf(columns []string, rows ...[]driver.Value) func(string, []driver.Value) (*fakeResult, error) {
+	return func(string, []driver.Value) (*fakeResult, error) {
+		return &fakeResult{columns: columns, rows: rows}, nil
+	}
+}
+
+func TestAll_Structs(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	db, fdb := newFakeDB(t, rowsOf(
+		[]string{"id", "users.name", "email", "team.id", "team.name", "created_at"},
+		[]driver.Value{int64(1), "Ann", "ann@example.com", int64(7), "Core", created},
+		[]driver.Value{int64(2), "Bob", nil, int64(8), "Ops", created},
+	))
+
+	qb := NewQuery().
+		Select("u.id", `u.name AS "users.name"`, "u.email", `t.id AS "team.id"`, `t.name AS "team.name"`, "u.created_at").
+		From("users u").
+		Join("teams t", "t.id = u.team_id", nil).
+		Where("u.active = ?", []any{true})
+
+	users, err := All[scanUser](context.Background(), NewRunner(db, Postgres), qb)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []scanUser{
+		{ID: 1, Name: "Ann", Email: sql.NullString{String: "ann@example.com", Valid: true}, Team: &scanTeam{ID: 7, Name: "Core"}, ScanAudit: &ScanAudit{CreatedAt: created}},
+		{ID: 2, Name: "Bob", Team: &scanTeam{ID: 8, Name: "Ops"}, ScanAudit: &ScanAudit{CreatedAt: created}},
+	}
+	if !reflect.DeepEqual(users, expected) {
+		t.Errorf("expected %+v, got %+v", expected, users)
+	}
+	if args := fdb.lastCall().args; !reflect.DeepEqual(args, []driver.Value{true}) {
+		t.Errorf("unexpected args: %v", args)
+	}
+}
+
+func TestAll_ScalarsAndMaps(t *testing.T) {
+	db, _ := newFakeDB(t, rowsOf([]string{"id"}, []driver.Value{int64(1)}, []driver.Value{int64(2)}))
+	ids, err := All[int64](context.Background(), db, NewQuery().Select("id").From("users"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(ids, []int64{1, 2}) {
+		t.Errorf("expected [1 2], got %v", ids)
+	}
+
+	db, _ = newFakeDB(t, rowsOf([]string{"id", "name"}, []driver.Value{int64(1), "Ann"}))
+	rows, err := All[map[string]any](context.Background(), db, NewQuery().Select("id", "name").From("users"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []map[string]any{{"id": int64(1), "name": "Ann"}}
+	if !reflect.DeepEqual(rows, expected) {
+		t.Errorf("expected %v, got %v", expected, rows)
+	}
+}
+
+func TestAll_UnmappedColumns(t *testing.T) {
+	db, _ := newFakeDB(t, rowsOf([]string{"id", "secret"}, []driver.Value{int64(1), "x"}))
+	qb := NewQuery().Select("id", "secret").From("teams")
+
+	if _, err := All[scanTeam](context.Background(), db, qb); err == nil {
+		t.Fatal("expected error for unmapped column")
+	}
+
+	teams, err := All[scanTeam](context.Background(), db, qb, IgnoreUnmapped())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(teams, []scanTeam{{ID: 1}}) {
+		t.Errorf("unexpected teams: %+v", teams)
+	}
+}
+
+func TestOne(t *testing.T) {
+	db, _ := newFakeDB(t, rowsOf([]string{"id", "name"}, []driver.Value{int64(7), "Core"}))
+	team, err := One[scanTeam](context.Background(), db, NewQuery().Select("id", "name").From("teams"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if team != (scanTeam{ID: 7, Name: "Core"}) {
+		t.Errorf("unexpected team: %+v", team)
+	}
+
+	db, _ = newFakeDB(t, rowsOf([]string{"id", "name"}))
+	_, err = One[scanTeam](context.Background(), db, NewQuery().Select("id", "name").From("teams"))
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("expected sql.ErrNoRows, got %v", err)
+	}
+}
+
+func TestAll_ShadowedFields(t *testing.T) {
+	db, _ := newFakeDB(t, rowsOf(
+		[]string{"id", "version", "email"},
+		[]driver.Value{int64(9), int64(2), "x@y.z"},
+	))
+
+	users, err := All[testShadowed](context.Background(), db, NewQuery().Select("id", "version", "email").From("users"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []testShadowed{{testBase: testBase{Version: 2}, ID: 9, Email: "x@y.z"}}
+	if !reflect.DeepEqual(users, expected) {
+		t.Errorf("expected %+v, got %+v", expected, users)
+	}
+
+	db, _ = newFakeDB(t, rowsOf([]string{"owner"}, []driver.Value{"a"}))
+	if _, err := All[testShadowed](context.Background(), db, NewQuery().Select("owner").From("users")); err == nil {
+		t.Error("expected an error for a column matching fields at the same depth")
+	}
+}
+
+func TestAll_ScalarColumnCount(t *testing.T) {
+	db, _ := newFakeDB(t, rowsOf([]string{"id", "name"}, []driver.Value{int64(1), "Ann"}))
+	if _, err := All[string](context.Background(), db, NewQuery().Select("id", "name").From("users")); err == nil {
+		t.Fatal("expected error scanning two columns into a scalar")
+	}
+}
@@ -0,0 +1,378 @@
+package queryx
+
+import (
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NullsOrder places NULL values within a sort key.
+type NullsOrder int
+
+const (
+	// NullsDefault keeps the dialect's ordering: NULLs sort as the largest
+	// values on Postgres and Oracle and as the smallest elsewhere.
+	NullsDefault NullsOrder = iota
+	NullsFirst
+	NullsLast
+)
+
+// SortKey is a column of a keyset pagination order. The keys of a Seek
+// must identify rows uniquely, typically by ending with the primary key.
+type SortKey struct {
+	Column string
+	Desc   bool
+	Nulls  NullsOrder
+	// Nullable marks a column that may hold NULL, so that the rows holding
+	// NULL are found on the side of the cursor where they sort.
+	Nullable bool
+}
+
+// nullsFirst reports whether NULLs of k come before other values on d.
+func (k SortKey) nullsFirst(d Dialect) bool {
+	switch k.Nulls {
+	case NullsFirst:
+		return true
+	case NullsLast:
+		return false
+	}
+	return d.Supports(FeatureNullsSortLow) != k.Desc
+}
+
+// Seek orders the query by keys and, when after is not empty, keeps only the
+// rows that sort after it. Use it with Limit for keyset pagination:
+//
+//	keys := []queryx.SortKey{{Column: "created_at", Desc: true}, {Column: "id", Desc: true}}
+//	qb.Seek(keys, after).Limit(50)
+//
+// renders "... WHERE (created_at < ? OR (created_at = ? AND id < ?))
+// ORDER BY created_at DESC, id DESC LIMIT ?", or the row value comparison
+// "(created_at, id) < (?, ?)" on dialects that support it when every key
+// has the same direction and no key is nullable. Seek replaces any order
+// set with OrderBy.
+func (qb *QueryBuilder) Seek(keys []SortKey, after Cursor) *QueryBuilder {
+	if len(keys) == 0 {
+		return qb.fail(errors.New("Seek: no sort keys"))
+	}
+	if len(after) > 0 && len(after) != len(keys) {
+		return qb.fail(fmt.Errorf("Seek: cursor has %d values for %d sort keys", len(after), len(keys)))
+	}
+
+	qb.orderByClause = nil
+	qb.seekKeys = keys
+	if len(after) > 0 {
+		qb.WhereCond(seekCond{keys: keys, values: after})
+	}
+	return qb
+}
+
+// sortKeyTerms renders the order by terms of the Seek keys. On dialects
+// without NULLS FIRST and NULLS LAST, an explicit NULL placement is
+// emulated with a leading CASE term.
+func sortKeyTerms(qb *QueryBuilder) []string {
+	d := qb.getDialect()
+	terms := make([]string, 0, len(qb.seekKeys))
+	for _, k := range qb.seekKeys {
+		column := quoteColumn(qb, k.Column)
+		term := column
+		if k.Desc {
+			term += " DESC"
+		}
+
+		nullsFirst := k.nullsFirst(d)
+		switch {
+		case k.Nulls == NullsDefault:
+		case d.Supports(FeatureNullsOrdering) && nullsFirst:
+			term += " NULLS FIRST"
+		case d.Supports(FeatureNullsOrdering):
+			term += " NULLS LAST"
+		case nullsFirst != (SortKey{Desc: k.Desc}).nullsFirst(d):
+			if nullsFirst {
+				terms = append(terms, "CASE WHEN "+column+" IS NULL THEN 0 ELSE 1 END")
+			} else {
+				terms = append(terms, "CASE WHEN "+column+" IS NULL THEN 1 ELSE 0 END")
+			}
+		}
+		terms = append(terms, term)
+	}
+	return terms
+}
+
+// seekCond matches the rows sorting after a cursor.
+type seekCond struct {
+	keys   []SortKey
+	values []any
+}
+
+func (c seekCond) appendSQL(qb *QueryBuilder, b *strings.Builder, args []any) ([]any, error) {
+	return c.expand(qb.getDialect()).appendSQL(qb, b, args)
+}
+
+// expand returns the condition as a row value comparison when d allows it,
+// and as the equivalent OR of AND terms otherwise.
+func (c seekCond) expand(d Dialect) Cond {
+	if c.canCompareRows(d) {
+		op := ">"
+		if c.keys[0].Desc {
+			op = "<"
+		}
+		columns := make([]string, len(c.keys))
+		for i, k := range c.keys {
+			columns[i] = k.Column
+		}
+		return rowCompareCond{columns: columns, op: op, values: c.values}
+	}
+
+	var branches []Cond
+	for i, k := range c.keys {
+		after, ok := c.after(d, k, c.values[i])
+		if !ok {
+			continue
+		}
+		conds := make([]Cond, 0, i+1)
+		for j := range i {
+			conds = append(conds, Eq(c.keys[j].Column, c.values[j]))
+		}
+		branches = append(branches, And(append(conds, after)...))
+	}
+	return Or(branches...)
+}
+
+func (c seekCond) canCompareRows(d Dialect) bool {
+	if len(c.keys) < 2 || !d.Supports(FeatureRowValues) {
+		return false
+	}
+	for i, k := range c.keys {
+		if k.Nullable || k.Desc != c.keys[0].Desc || c.values[i] == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// after returns the condition matching values of k sorting after v. It
+// reports false when nothing sorts after v, as for a NULL sorting last.
+func (c seekCond) after(d Dialect, k SortKey, v any) (Cond, bool) {
+	nullsFirst := k.nullsFirst(d)
+	if v == nil {
+		if nullsFirst {
+			return IsNotNull(k.Column), true
+		}
+		return nil, false
+	}
+
+	var cmp Cond
+	if k.Desc {
+		cmp = Lt(k.Column, v)
+	} else {
+		cmp = Gt(k.Column, v)
+	}
+	if k.Nullable && !nullsFirst {
+		return Or(cmp, IsNull(k.Column)), true
+	}
+	return cmp, true
+}
+
+// rowCompareCond renders "(a, b) > (?, ?)".
+type rowCompareCond struct {
+	columns []string
+	op      string
+	values  []any
+}
+
+func (c rowCompareCond) appendSQL(qb *QueryBuilder, b *strings.Builder, args []any) ([]any, error) {
+	b.WriteString("(" + strings.Join(quoteColumns(qb, c.columns), ", ") + ") " + c.op + " (")
+	b.WriteString(strings.TrimSuffix(strings.Repeat("?, ", len(c.values)), ", ") + ")")
+	return append(args, c.values...), nil
+}
+
+// Cursor holds the sort key values of the last row of a page, in the order
+// of the keys passed to Seek.
+type Cursor []any
+
+// CursorFrom reads the values of keys from row, a struct scanned with All
+// or a map[string]any. Key columns are matched as All matches result
+// columns, so "u.created_at" finds a field tagged "created_at".
+func CursorFrom(row any, keys []SortKey) (Cursor, error) {
+	rv := reflect.ValueOf(row)
+	for rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+
+	cursor := make(Cursor, len(keys))
+	for i, k := range keys {
+		var v any
+		switch rv.Kind() {
+		case reflect.Struct:
+			index, ok := matchColumn(scanFields(rv.Type()), k.Column)
+			if !ok {
+				return nil, fmt.Errorf("CursorFrom: no field for column %q in %s", k.Column, rv.Type())
+			}
+			v = fieldInterface(rv, index)
+		case reflect.Map:
+			m, ok := rv.Interface().(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("CursorFrom: unsupported row type %T", row)
+			}
+			if v, ok = m[k.Column]; !ok {
+				column := k.Column[strings.LastIndexByte(k.Column, '.')+1:]
+				if v, ok = m[column]; !ok {
+					return nil, fmt.Errorf("CursorFrom: no value for column %q", k.Column)
+				}
+			}
+		default:
+			return nil, fmt.Errorf("CursorFrom: unsupported row type %T", row)
+		}
+
+		v, err := cursorValue(v)
+		if err != nil {
+			return nil, fmt.Errorf("CursorFrom: column %q: %w", k.Column, err)
+		}
+		cursor[i] = v
+	}
+	return cursor, nil
+}
+
+// cursorValue resolves pointers and driver.Valuer implementations such as
+// sql.NullString to the value compared by the database, and values of named
+// types such as "type OrderID int64" to their underlying basic type.
+func cursorValue(v any) (any, error) {
+	for {
+		if v == nil {
+			return nil, nil
+		}
+		if valuer, ok := v.(driver.Valuer); ok {
+			value, err := valuer.Value()
+			if err != nil {
+				return nil, err
+			}
+			return value, nil
+		}
+		rv := reflect.ValueOf(v)
+		if rv.Kind() != reflect.Pointer {
+			return basicValue(rv), nil
+		}
+		if rv.IsNil() {
+			return nil, nil
+		}
+		v = rv.Elem().Interface()
+	}
+}
+
+// basicValue converts rv, when its type is a named one such as
+// "type OrderID int64", to the basic type of its kind. Other values are
+// returned as they are.
+func basicValue(rv reflect.Value) any {
+	if rv.Type().PkgPath() == "" {
+		return rv.Interface()
+	}
+	switch rv.Kind() {
+	case reflect.Bool:
+		return rv.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return rv.Uint()
+	case reflect.Float32, reflect.Float64:
+		return rv.Float()
+	case reflect.String:
+		return rv.String()
+	case reflect.Slice:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return rv.Bytes()
+		}
+	}
+	return rv.Interface()
+}
+
+// Encode returns c as an opaque URL-safe token. Values keep their type
+// through DecodeCursor: integers decode as int64 or uint64, floats as
+// float64, and time.Time, []byte, string and bool as themselves. Values of
+// named types encode as their underlying type.
+func (c Cursor) Encode() (string, error) {
+	encoded := make([][2]string, len(c))
+	for i, v := range c {
+		if v != nil {
+			v = basicValue(reflect.ValueOf(v))
+		}
+		switch v := v.(type) {
+		case nil:
+			encoded[i] = [2]string{"n", ""}
+		case bool:
+			encoded[i] = [2]string{"b", strconv.FormatBool(v)}
+		case int, int8, int16, int32, int64:
+			encoded[i] = [2]string{"i", strconv.FormatInt(reflect.ValueOf(v).Int(), 10)}
+		case uint, uint8, uint16, uint32, uint64:
+			encoded[i] = [2]string{"u", strconv.FormatUint(reflect.ValueOf(v).Uint(), 10)}
+		case float32, float64:
+			encoded[i] = [2]string{"f", strconv.FormatFloat(reflect.ValueOf(v).Float(), 'g', -1, 64)}
+		case string:
+			encoded[i] = [2]string{"s", v}
+		case []byte:
+			encoded[i] = [2]string{"x", base64.StdEncoding.EncodeToString(v)}
+		case time.Time:
+			encoded[i] = [2]string{"t", v.Format(time.RFC3339Nano)}
+		default:
+			return "", fmt.Errorf("cursor value %d has unsupported type %T", i, v)
+		}
+	}
+
+	data, err := json.Marshal(encoded)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// DecodeCursor parses a token returned by Cursor.Encode. An empty token
+// decodes to an empty cursor, which Seek treats as the first page.
+func DecodeCursor(token string) (Cursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", err)
+	}
+	var encoded [][2]string
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", err)
+	}
+
+	cursor := make(Cursor, len(encoded))
+	for i, e := range encoded {
+		var v any
+		var err error
+		switch e[0] {
+		case "n":
+		case "b":
+			v, err = strconv.ParseBool(e[1])
+		case "i":
+			v, err = strconv.ParseInt(e[1], 10, 64)
+		case "u":
+			v, err = strconv.ParseUint(e[1], 10, 64)
+		case "f":
+			v, err = strconv.ParseFloat(e[1], 64)
+		case "s":
+			v = e[1]
+		case "x":
+			v, err = base64.StdEncoding.DecodeString(e[1])
+		case "t":
+			v, err = time.Parse(time.RFC3339Nano, e[1])
+		default:
+			err = fmt.Errorf("unknown type %q", e[0])
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid cursor value %d: %w", i, err)
+		}
+		cursor[i] = v
+	}
+	return cursor, nil
+}
@@ -0,0 +1,271 @@
+package queryx
+
+import (
+	"bytes"
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSeek(t *testing.T) {
+	byNewest := []SortKey{{Column: "created_at", Desc: true}, {Column: "id", Desc: true}}
+	byScore := []SortKey{{Column: "score", Desc: true}, {Column: "id"}}
+
+	cases := []struct {
+		name         string
+		qb           *QueryBuilder
+		expectedExpr string
+		expectedArgs []any
+	}{
+		{
+			"first page",
+			NewQuery().Select("id").From("posts").Seek(byNewest, nil).Limit(20),
+			"SELECT id FROM posts ORDER BY created_at DESC, id DESC LIMIT ?",
+			[]any{20},
+		},
+		{
+			"single key",
+			NewQuery().Select("id").From("posts").Seek([]SortKey{{Column: "id"}}, Cursor{42}).Limit(20),
+			"SELECT id FROM posts WHERE id > ? ORDER BY id LIMIT ?",
+			[]any{42, 20},
+		},
+		{
+			"row values",
+			NewQuery().
+				WithDialect(Postgres).
+				Select("id").
+				From("posts").
+				Where("author_id = ?", []any{7}).
+				Seek(byNewest, Cursor{"2024-01-02", 9}).
+				Limit(20),
+			"SELECT id FROM posts WHERE author_id = $1 AND (created_at, id) < ($2, $3) ORDER BY created_at DESC, id DESC LIMIT $4",
+			[]any{7, "2024-01-02", 9, 20},
+		},
+		{
+			"mixed directions",
+			NewQuery().
+				WithDialect(Postgres).
+				Select("id").
+				From("players").
+				Where("team_id = ?", []any{3}).
+				Seek(byScore, Cursor{90, 5}).
+				Limit(10),
+			"SELECT id FROM players WHERE team_id = $1 AND (score < $2 OR (score = $3 AND id > $4)) ORDER BY score DESC, id LIMIT $5",
+			[]any{3, 90, 90, 5, 10},
+		},
+		{
+			"expanded without row values",
+			NewQuery().
+				WithDialect(SQLServer).
+				Select("id").
+				From("posts").
+				Seek(byNewest, Cursor{"2024-01-02", 9}).
+				Limit(20),
+			"SELECT TOP (@p1) id FROM posts WHERE created_at < @p2 OR (created_at = @p3 AND id < @p4) ORDER BY created_at DESC, id DESC",
+			[]any{20, "2024-01-02", "2024-01-02", 9},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			expr, args, err := c.qb.Build()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if expr != c.expectedExpr {
+				t.Errorf("expected SQL:\n%s\ngot:\n%s", c.expectedExpr, expr)
+			}
+			if !reflect.DeepEqual(args, c.expectedArgs) {
+				t.Errorf("expected args %v, got %v", c.expectedArgs, args)
+			}
+		})
+	}
+}
+
+func TestSeek_Nulls(t *testing.T) {
+	cases := []struct {
+		name         string
+		qb           *QueryBuilder
+		expectedExpr string
+		expectedArgs []any
+	}{
+		{
+			"nullable value sorting last",
+			NewQuery().
+				WithDialect(Postgres).
+				Select("id").
+				From("tasks").
+				Seek([]SortKey{{Column: "due", Nullable: true}, {Column: "id"}}, Cursor{"2024-05-01", 3}),
+			"SELECT id FROM tasks WHERE due > $1 OR due IS NULL OR (due = $2 AND id > $3) ORDER BY due, id",
+			[]any{"2024-05-01", "2024-05-01", 3},
+		},
+		{
+			"null cursor sorting last",
+			NewQuery().
+				WithDialect(Postgres).
+				Select("id").
+				From("tasks").
+				Seek([]SortKey{{Column: "due", Nullable: true}, {Column: "id"}}, Cursor{nil, 3}),
+			"SELECT id FROM tasks WHERE due IS NULL AND id > $1 ORDER BY due, id",
+			[]any{3},
+		},
+		{
+			"null cursor sorting first",
+			NewQuery().
+				WithDialect(MySQL).
+				Select("id").
+				From("tasks").
+				Seek([]SortKey{{Column: "due", Nullable: true}, {Column: "id"}}, Cursor{nil, 3}),
+			"SELECT id FROM tasks WHERE due IS NOT NULL OR (due IS NULL AND id > ?) ORDER BY due, id",
+			[]any{3},
+		},
+		{
+			"explicit nulls last",
+			NewQuery().
+				WithDialect(Postgres).
+				Select("id").
+				From("tasks").
+				Seek([]SortKey{{Column: "due", Desc: true, Nulls: NullsLast, Nullable: true}, {Column: "id"}}, Cursor{"2024-05-01", 3}),
+			"SELECT id FROM tasks WHERE due < $1 OR due IS NULL OR (due = $2 AND id > $3) ORDER BY due DESC NULLS LAST, id",
+			[]any{"2024-05-01", "2024-05-01", 3},
+		},
+		{
+			"emulated nulls last",
+			NewQuery().
+				WithDialect(MySQL).
+				Select("id").
+				From("tasks").
+				Seek([]SortKey{{Column: "due", Nulls: NullsLast, Nullable: true}, {Column: "id"}}, nil),
+			"SELECT id FROM tasks ORDER BY CASE WHEN due IS NULL THEN 1 ELSE 0 END, due, id",
+			nil,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			expr, args, err := c.qb.Build()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if expr != c.expectedExpr {
+				t.Errorf("expected SQL:\n%s\ngot:\n%s", c.expectedExpr, expr)
+			}
+			if !reflect.DeepEqual(args, c.expectedArgs) {
+				t.Errorf("expected args %v, got %v", c.expectedArgs, args)
+			}
+		})
+	}
+}
+
+func TestSeek_Errors(t *testing.T) {
+	if _, _, err := NewQuery().Select("id").From("posts").Seek(nil, nil).Build(); err == nil {
+		t.Error("expected an error for missing sort keys")
+	}
+	if _, _, err := NewQuery().Select("id").From("posts").Seek([]SortKey{{Column: "id"}}, Cursor{1, 2}).Build(); err == nil {
+		t.Error("expected an error for a cursor of the wrong length")
+	}
+}
+
+func TestCursor_RoundTrip(t *testing.T) {
+	at := time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC)
+	cursor := Cursor{nil, true, 42, uint8(7), 1.5, "a/b", []byte{0, 1}, at}
+
+	token, err := cursor.Encode()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	decoded, err := DecodeCursor(token)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := Cursor{nil, true, int64(42), uint64(7), 1.5, "a/b", []byte{0, 1}, at}
+	for i := range expected {
+		if b, ok := expected[i].([]byte); ok {
+			if !bytes.Equal(b, decoded[i].([]byte)) {
+				t.Errorf("value %d: expected %v, got %v", i, b, decoded[i])
+			}
+			continue
+		}
+		if t2, ok := expected[i].(time.Time); ok {
+			if !t2.Equal(decoded[i].(time.Time)) {
+				t.Errorf("value %d: expected %v, got %v", i, t2, decoded[i])
+			}
+			continue
+		}
+		if decoded[i] != expected[i] {
+			t.Errorf("value %d: expected %#v, got %#v", i, expected[i], decoded[i])
+		}
+	}
+
+	if _, err := DecodeCursor("not a cursor"); err == nil {
+		t.Error("expected an error for an invalid token")
+	}
+	if _, err := (Cursor{struct{}{}}).Encode(); err == nil {
+		t.Error("expected an error for an unsupported value")
+	}
+}
+
+func TestCursorFrom(t *testing.T) {
+	type post struct {
+		ID        int64          `db:"id"`
+		CreatedAt time.Time      `db:"created_at"`
+		Title     sql.NullString `db:"title"`
+		Rank      *int           `db:"rank"`
+	}
+	at := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	row := post{ID: 9, CreatedAt: at, Title: sql.NullString{String: "hi", Valid: true}}
+	keys := []SortKey{{Column: "p.created_at"}, {Column: "title"}, {Column: "rank"}, {Column: "id"}}
+
+	cursor, err := CursorFrom(&row, keys)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := Cursor{at, "hi", nil, int64(9)}
+	if !reflect.DeepEqual(cursor, expected) {
+		t.Errorf("expected %v, got %v", expected, cursor)
+	}
+
+	cursor, err = CursorFrom(map[string]any{"created_at": at, "id": 9}, []SortKey{{Column: "p.created_at"}, {Column: "id"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(cursor, Cursor{at, 9}) {
+		t.Errorf("unexpected cursor %v", cursor)
+	}
+
+	if _, err := CursorFrom(row, []SortKey{{Column: "missing"}}); err == nil {
+		t.Error("expected an error for an unknown column")
+	}
+}
+
+func TestCursor_NamedTypes(t *testing.T) {
+	type orderID int64
+	type status string
+	type order struct {
+		ID     orderID `db:"id"`
+		Status status  `db:"status"`
+	}
+	keys := []SortKey{{Column: "status"}, {Column: "id"}}
+
+	cursor, err := CursorFrom(order{ID: 42, Status: "paid"}, keys)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(cursor, Cursor{"paid", int64(42)}) {
+		t.Errorf("unexpected cursor %#v", cursor)
+	}
+
+	token, err := (Cursor{status("paid"), orderID(42)}).Encode()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	decoded, err := DecodeCursor(token)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(decoded, Cursor{"paid", int64(42)}) {
+		t.Errorf("unexpected decoded cursor %#v", decoded)
+	}
+}
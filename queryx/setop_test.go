@@ -0,0 +1,126 @@
+package queryx
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSetOperations_Union(t *testing.T) {
+	customers := NewQuery().Select("email").From("customers").Where("active = ?", []any{true})
+	leads := NewQuery().Select("email").From("leads").Where("source = ?", []any{"web"})
+	staff := NewQuery().Select("email").From("staff")
+
+	qb := NewQuery().
+		WithDialect(Postgres).
+		Select("email").
+		From("users").
+		Where("verified = ?", []any{true}).
+		Union(customers).
+		UnionAll(leads).
+		Except(staff).
+		OrderBy("email").
+		Limit(10).
+		Offset(20)
+
+	sql, args, err := qb.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedExpr := "SELECT email FROM users WHERE verified = $1 UNION SELECT email FROM customers WHERE active = $2 UNION ALL SELECT email FROM leads WHERE source = $3 EXCEPT SELECT email FROM staff ORDER BY email LIMIT $4 OFFSET $5"
+	expectedArgs := []any{true, true, "web", 10, 20}
+
+	if sql != expectedExpr {
+		t.Errorf("expected SQL:\n%s\ngot:\n%s", expectedExpr, sql)
+	}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("expected args: %v, got: %v", expectedArgs, args)
+	}
+}
+
+func TestSetOperations_ParenthesizedBranch(t *testing.T) {
+	top := NewQuery().Select("id").From("scores").OrderBy("score DESC").Limit(5)
+
+	qb := NewQuery().
+		Select("id").
+		From("pinned").
+		Intersect(top)
+
+	sql, args, err := qb.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedExpr := "SELECT id FROM pinned INTERSECT (SELECT id FROM scores ORDER BY score DESC LIMIT ?)"
+	expectedArgs := []any{5}
+
+	if sql != expectedExpr {
+		t.Errorf("expected SQL:\n%s\ngot:\n%s", expectedExpr, sql)
+	}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("expected args: %v, got: %v", expectedArgs, args)
+	}
+}
+
+func TestSetOperations_SQLiteRejectsParenthesizedBranch(t *testing.T) {
+	qb := NewQuery().
+		WithDialect(SQLite).
+		Select("id").
+		From("a").
+		Union(NewQuery().Select("id").From("b").Limit(1))
+
+	if _, _, err := qb.Build(); err == nil {
+		t.Fatal("expected error for limited union branch on sqlite")
+	}
+}
+
+func TestSetOperations_SQLServerLimit(t *testing.T) {
+	qb := NewQuery().
+		WithDialect(SQLServer).
+		Select("id").
+		From("a").
+		Union(NewQuery().Select("id").From("b")).
+		OrderBy("id").
+		Limit(10)
+
+	sql, args, err := qb.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedExpr := "SELECT id FROM a UNION SELECT id FROM b ORDER BY id OFFSET 0 ROWS FETCH NEXT @p1 ROWS ONLY"
+	expectedArgs := []any{10}
+
+	if sql != expectedExpr {
+		t.Errorf("expected SQL:\n%s\ngot:\n%s", expectedExpr, sql)
+	}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("expected args: %v, got: %v", expectedArgs, args)
+	}
+}
+
+func TestSetOperations_CountTotal(t *testing.T) {
+	qb := NewQuery().
+		WithDialect(Postgres).
+		Select("email").
+		From("users").
+		Where("verified = ?", []any{true}).
+		Union(NewQuery().Select("email").From("leads").Where("source = ?", []any{"web"})).
+		OrderBy("email").
+		Limit(10)
+
+	sql, args, err := qb.CountTotal().Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedExpr := "SELECT COUNT(*) FROM (SELECT email FROM users WHERE verified = $1 UNION SELECT email FROM leads WHERE source = $2) AS subquery"
+	expectedArgs := []any{true, "web"}
+
+	if sql != expectedExpr {
+		t.Errorf("expected SQL:\n%s\ngot:\n%s", expectedExpr, sql)
+	}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("expected args: %v, got: %v", expectedArgs, args)
+	}
+}
@@ -0,0 +1,281 @@
+package queryx
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// UpdateOptions selects the struct fields written by UpdateStruct.
+type UpdateOptions struct {
+	// Columns limits the update to the listed columns.
+	Columns []string
+	// Omit excludes the listed columns, such as the primary key.
+	Omit []string
+}
+
+// structField is a struct field mapped to a column through its db tag.
+type structField struct {
+	column    string
+	index     []int
+	omitEmpty bool
+	readOnly  bool
+}
+
+// structFieldsCache holds the []structField of each struct type.
+var structFieldsCache sync.Map
+
+// structFields returns the column fields of t, a struct type, as listed by
+// dbFields. The tag options "omitempty" and "readonly" skip a field when it
+// holds its zero value or always.
+func structFields(t reflect.Type) []structField {
+	if cached, ok := structFieldsCache.Load(t); ok {
+		return cached.([]structField)
+	}
+
+	var fields []structField
+	for _, f := range dbFields(t, false) {
+		field := structField{column: f.name, index: f.index}
+		for _, opt := range strings.Split(f.opts, ",") {
+			switch opt {
+			case "omitempty":
+				field.omitEmpty = true
+			case "readonly":
+				field.readOnly = true
+			}
+		}
+		fields = append(fields, field)
+	}
+	cached, _ := structFieldsCache.LoadOrStore(t, fields)
+	return cached.([]structField)
+}
+
+// dbField is a struct field named after a column.
+type dbField struct {
+	name  string
+	index []int
+	opts  string
+}
+
+// dbFields lists the fields of t, a struct type, in declaration order.
+// Columns are named by the db tag, or by the lowercased field name when the
+// tag is missing, as sqlx does, and a "-" tag ignores the field.
+//
+// Fields of untagged embedded structs are promoted as Go promotes them: a
+// field hides the deeper fields of the same name, and names clashing at the
+// same depth are dropped. With nested, the fields of a struct tagged
+// "prefix" are named "prefix.column", as All reads them.
+func dbFields(t reflect.Type, nested bool) []dbField {
+	all := appendDBFields(nil, t, nil, "", nested)
+
+	depth := map[string]int{}
+	clashes := map[string]bool{}
+	for _, f := range all {
+		d, ok := depth[f.name]
+		switch {
+		case !ok || len(f.index) < d:
+			depth[f.name] = len(f.index)
+			clashes[f.name] = false
+		case len(f.index) == d:
+			clashes[f.name] = true
+		}
+	}
+
+	fields := make([]dbField, 0, len(all))
+	for _, f := range all {
+		if len(f.index) == depth[f.name] && !clashes[f.name] {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+func appendDBFields(fields []dbField, t reflect.Type, index []int, prefix string, nested bool) []dbField {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, hasTag := f.Tag.Lookup("db")
+		if tag == "-" {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(tag, ",")
+		fieldIndex := append(slices.Clip(index), i)
+
+		ft := f.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if ft.Kind() == reflect.Struct && !isScannable(ft) {
+			switch {
+			case f.Anonymous && (!hasTag || name == ""):
+				// Pointers to unexported embedded structs cannot be
+				// allocated through reflection when scanning, as with
+				// encoding/json.
+				if !nested || f.IsExported() || f.Type.Kind() != reflect.Pointer {
+					fields = appendDBFields(fields, ft, fieldIndex, prefix, nested)
+				}
+				continue
+			case nested && name != "" && f.IsExported():
+				fields = appendDBFields(fields, ft, fieldIndex, prefix+name+".", nested)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+		fields = append(fields, dbField{name: prefix + name, index: fieldIndex, opts: opts})
+	}
+	return fields
+}
+
+// structValue returns the struct held by v, dereferencing pointers.
+func structValue(v any) (reflect.Value, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("expected a struct or a pointer to a struct, got %T", v)
+	}
+	return rv, nil
+}
+
+// fieldValue returns the field at index. It reports false when the path
+// goes through a nil embedded pointer.
+func fieldValue(rv reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && rv.Kind() == reflect.Pointer {
+			if rv.IsNil() {
+				return reflect.Value{}, false
+			}
+			rv = rv.Elem()
+		}
+		rv = rv.Field(x)
+	}
+	return rv, true
+}
+
+func fieldInterface(rv reflect.Value, index []int) any {
+	fv, ok := fieldValue(rv, index)
+	if !ok {
+		return nil
+	}
+	return fv.Interface()
+}
+
+func fieldIsZero(rv reflect.Value, index []int) bool {
+	fv, ok := fieldValue(rv, index)
+	return !ok || fv.IsZero()
+}
+
+// InsertStruct inserts the db tagged fields of v, a struct or a pointer to
+// one. Read-only fields are skipped, as are omitempty fields holding their
+// zero value.
+func (qb *QueryBuilder) InsertStruct(table string, v any) *QueryBuilder {
+	rv, err := structValue(v)
+	if err != nil {
+		return qb.fail(fmt.Errorf("InsertStruct: %w", err))
+	}
+
+	var columns []string
+	var values []any
+	for _, f := range structFields(rv.Type()) {
+		if f.readOnly || (f.omitEmpty && fieldIsZero(rv, f.index)) {
+			continue
+		}
+		columns = append(columns, f.column)
+		values = append(values, fieldInterface(rv, f.index))
+	}
+	if len(columns) == 0 {
+		return qb.fail(fmt.Errorf("InsertStruct: %s has no columns to insert", rv.Type()))
+	}
+	return qb.Insert(table, columns).Values(values...)
+}
+
+// InsertStructs inserts one row per element of rows, a slice of structs or
+// of pointers to structs of the same type. An omitempty column is left out
+// only when it holds its zero value in every row.
+func (qb *QueryBuilder) InsertStructs(table string, rows any) *QueryBuilder {
+	rv := reflect.ValueOf(rows)
+	if rv.Kind() != reflect.Slice {
+		return qb.fail(fmt.Errorf("InsertStructs: expected a slice, got %T", rows))
+	}
+	if rv.Len() == 0 {
+		return qb.fail(errors.New("InsertStructs: no rows to insert"))
+	}
+
+	elems := make([]reflect.Value, rv.Len())
+	for i := range elems {
+		elem, err := structValue(rv.Index(i).Interface())
+		if err != nil {
+			return qb.fail(fmt.Errorf("InsertStructs: row %d: %w", i, err))
+		}
+		if i > 0 && elem.Type() != elems[0].Type() {
+			return qb.fail(fmt.Errorf("InsertStructs: row %d is a %s, want %s", i, elem.Type(), elems[0].Type()))
+		}
+		elems[i] = elem
+	}
+
+	var fields []structField
+	for _, f := range structFields(elems[0].Type()) {
+		if f.readOnly {
+			continue
+		}
+		if f.omitEmpty && !slices.ContainsFunc(elems, func(e reflect.Value) bool { return !fieldIsZero(e, f.index) }) {
+			continue
+		}
+		fields = append(fields, f)
+	}
+	if len(fields) == 0 {
+		return qb.fail(fmt.Errorf("InsertStructs: %s has no columns to insert", elems[0].Type()))
+	}
+
+	columns := make([]string, len(fields))
+	for i, f := range fields {
+		columns[i] = f.column
+	}
+	values := make([][]any, len(elems))
+	for i, elem := range elems {
+		values[i] = make([]any, len(fields))
+		for j, f := range fields {
+			values[i][j] = fieldInterface(elem, f.index)
+		}
+	}
+	return qb.Insert(table, columns).MultiValues(values)
+}
+
+// UpdateStruct updates table with the db tagged fields of v, a struct or a
+// pointer to one. Read-only fields are skipped, as are omitempty fields
+// holding their zero value; opts may further limit the columns. Add a Where
+// clause to select the rows to update.
+func (qb *QueryBuilder) UpdateStruct(table string, v any, opts *UpdateOptions) *QueryBuilder {
+	rv, err := structValue(v)
+	if err != nil {
+		return qb.fail(fmt.Errorf("UpdateStruct: %w", err))
+	}
+	if opts == nil {
+		opts = &UpdateOptions{}
+	}
+
+	qb.Update(table, nil)
+	for _, f := range structFields(rv.Type()) {
+		switch {
+		case f.readOnly, f.omitEmpty && fieldIsZero(rv, f.index):
+		case len(opts.Columns) > 0 && !slices.Contains(opts.Columns, f.column):
+		case slices.Contains(opts.Omit, f.column):
+		default:
+			qb.SetExpr(f.column, "?", []any{bindValue{fieldInterface(rv, f.index)}})
+		}
+	}
+	if len(qb.setClause) == 0 {
+		return qb.fail(fmt.Errorf("UpdateStruct: %s has no columns to update", rv.Type()))
+	}
+	return qb
+}
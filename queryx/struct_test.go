@@ -0,0 +1,204 @@
+package queryx
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type testTimestamps struct {
+	CreatedAt time.Time `db:"created_at,readonly"`
+	UpdatedAt time.Time `db:"updated_at,omitempty"`
+}
+
+type testUser struct {
+	ID    int    `db:"id,readonly"`
+	Name  string `db:"name"`
+	Email string `db:"email,omitempty"`
+	Notes string `db:"-"`
+	Age   int
+	*testTimestamps
+	secret string
+}
+
+func TestInsertStruct(t *testing.T) {
+	qb := NewQuery().
+		WithDialect(Postgres).
+		InsertStruct("users", &testUser{ID: 9, Name: "Ann", Notes: "x", Age: 30, secret: "s"}).
+		Returning("id")
+
+	sql, args, err := qb.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedExpr := "INSERT INTO users (name, age) VALUES ($1, $2) RETURNING id"
+	expectedArgs := []any{"Ann", 30}
+
+	if sql != expectedExpr {
+		t.Errorf("expected SQL:\n%s\ngot:\n%s", expectedExpr, sql)
+	}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("expected args: %v, got: %v", expectedArgs, args)
+	}
+}
+
+func TestInsertStructs(t *testing.T) {
+	updated := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	users := []testUser{
+		{Name: "Ann", Age: 30},
+		{Name: "Bob", Email: "bob@example.com", Age: 40, testTimestamps: &testTimestamps{UpdatedAt: updated}},
+	}
+
+	sql, args, err := NewQuery().InsertStructs("users", users).Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedExpr := "INSERT INTO users (name, email, age, updated_at) VALUES (?, ?, ?, ?), (?, ?, ?, ?)"
+	expectedArgs := []any{"Ann", "", 30, nil, "Bob", "bob@example.com", 40, updated}
+
+	if sql != expectedExpr {
+		t.Errorf("expected SQL:\n%s\ngot:\n%s", expectedExpr, sql)
+	}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("expected args: %v, got: %v", expectedArgs, args)
+	}
+}
+
+func TestUpdateStruct(t *testing.T) {
+	user := testUser{ID: 9, Name: "Ann", Email: "ann@example.com", Age: 30}
+
+	cases := []struct {
+		name         string
+		opts         *UpdateOptions
+		expectedExpr string
+		expectedArgs []any
+	}{
+		{
+			"all columns",
+			nil,
+			"UPDATE users SET name = ?, email = ?, age = ? WHERE id = ?",
+			[]any{"Ann", "ann@example.com", 30, 9},
+		},
+		{
+			"selected columns",
+			&UpdateOptions{Columns: []string{"name", "age"}},
+			"UPDATE users SET name = ?, age = ? WHERE id = ?",
+			[]any{"Ann", 30, 9},
+		},
+		{
+			"omitted columns",
+			&UpdateOptions{Omit: []string{"email"}},
+			"UPDATE users SET name = ?, age = ? WHERE id = ?",
+			[]any{"Ann", 30, 9},
+		},
+	}
+
+	for _, c := range cases {
+		sql, args, err := NewQuery().UpdateStruct("users", user, c.opts).Where("id = ?", []any{user.ID}).Build()
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", c.name, err)
+		}
+		if sql != c.expectedExpr {
+			t.Errorf("%s: expected SQL:\n%s\ngot:\n%s", c.name, c.expectedExpr, sql)
+		}
+		if !reflect.DeepEqual(args, c.expectedArgs) {
+			t.Errorf("%s: expected args: %v, got: %v", c.name, c.expectedArgs, args)
+		}
+	}
+}
+
+type testPost struct {
+	ID   int      `db:"id,readonly"`
+	Tags []string `db:"tags"`
+}
+
+func TestUpdateStruct_SliceField(t *testing.T) {
+	post := testPost{ID: 3, Tags: []string{"a", "b"}}
+
+	sql, args, err := NewQuery().WithDialect(Postgres).UpdateStruct("posts", post, nil).Where("id = ?", []any{post.ID}).Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedExpr := "UPDATE posts SET tags = $1 WHERE id = $2"
+	expectedArgs := []any{[]string{"a", "b"}, 3}
+
+	if sql != expectedExpr {
+		t.Errorf("expected SQL:\n%s\ngot:\n%s", expectedExpr, sql)
+	}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("expected args: %v, got: %v", expectedArgs, args)
+	}
+}
+
+type testBase struct {
+	ID      int    `db:"id"`
+	Version int    `db:"version"`
+	Owner   string `db:"owner"`
+}
+
+type testAudit struct {
+	Owner string `db:"owner"`
+}
+
+type testShadowed struct {
+	testBase
+	testAudit
+	ID    int    `db:"id"`
+	Email string `db:"email"`
+}
+
+func TestStruct_ShadowedFields(t *testing.T) {
+	v := testShadowed{testBase: testBase{ID: 1, Version: 2, Owner: "a"}, testAudit: testAudit{Owner: "b"}, ID: 9, Email: "x@y.z"}
+
+	sql, args, err := NewQuery().InsertStruct("users", v).Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectedExpr := "INSERT INTO users (version, id, email) VALUES (?, ?, ?)"
+	expectedArgs := []any{2, 9, "x@y.z"}
+	if sql != expectedExpr {
+		t.Errorf("expected SQL:\n%s\ngot:\n%s", expectedExpr, sql)
+	}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("expected args: %v, got: %v", expectedArgs, args)
+	}
+
+	sql, _, err = NewQuery().UpdateStruct("users", v, nil).Where("id = ?", []any{9}).Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectedExpr = "UPDATE users SET version = ?, id = ?, email = ? WHERE id = ?"
+	if sql != expectedExpr {
+		t.Errorf("expected SQL:\n%s\ngot:\n%s", expectedExpr, sql)
+	}
+}
+
+func TestStructFields_Cached(t *testing.T) {
+	typ := reflect.TypeOf(testUser{})
+	first := structFields(typ)
+	second := structFields(typ)
+	if &first[0] != &second[0] {
+		t.Error("expected struct fields to be cached per type")
+	}
+}
+
+func TestStruct_Errors(t *testing.T) {
+	cases := []struct {
+		name string
+		qb   *QueryBuilder
+	}{
+		{"insert non struct", NewQuery().InsertStruct("users", 1)},
+		{"insert empty slice", NewQuery().InsertStructs("users", []testUser{})},
+		{"insert mixed slice", NewQuery().InsertStructs("users", []any{testUser{}, testTimestamps{}})},
+		{"update nothing", NewQuery().UpdateStruct("users", testUser{}, &UpdateOptions{Columns: []string{"id"}}).Where("id = ?", []any{1})},
+	}
+
+	for _, c := range cases {
+		if _, _, err := c.qb.Build(); err == nil {
+			t.Errorf("%s: expected error", c.name)
+		}
+	}
+}
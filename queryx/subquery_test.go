@@ -0,0 +1,222 @@
+package queryx
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSubquery_FromSubquery(t *testing.T) {
+	latest := NewQuery().
+		Select("user_id", "MAX(created_at) AS created_at").
+		From("orders").
+		Where("status = ?", []any{"paid"}).
+		GroupBy("user_id")
+
+	qb := NewQuery().
+		WithDialect(Postgres).
+		Select("l.user_id").
+		FromSubquery(latest, "l").
+		Where("l.created_at > ?", []any{"2024-01-01"})
+
+	sql, args, err := qb.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedExpr := "SELECT l.user_id FROM (SELECT user_id, MAX(created_at) AS created_at FROM orders WHERE status = $1 GROUP BY user_id) AS l WHERE l.created_at > $2"
+	expectedArgs := []any{"paid", "2024-01-01"}
+
+	if sql != expectedExpr {
+		t.Errorf("expected SQL:\n%s\ngot:\n%s", expectedExpr, sql)
+	}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("expected args: %v, got: %v", expectedArgs, args)
+	}
+}
+
+func TestSubquery_JoinSubquery(t *testing.T) {
+	totals := NewQuery().
+		Select("user_id", "SUM(total) AS total").
+		From("orders").
+		Where("created_at > ?", []any{"2024-01-01"}).
+		GroupBy("user_id")
+
+	qb := NewQuery().
+		WithDialect(Postgres).
+		Select("users.id", "t.total").
+		From("users").
+		JoinSubquery(totals, "t", "t.user_id = users.id AND t.total > ?", []any{100}).
+		LeftJoinSubquery(NewQuery().Select("user_id").From("bans"), "b", "b.user_id = users.id", nil).
+		Where("users.active = ?", []any{true})
+
+	sql, args, err := qb.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedExpr := "SELECT users.id, t.total FROM users INNER JOIN (SELECT user_id, SUM(total) AS total FROM orders WHERE created_at > $1 GROUP BY user_id) AS t ON t.user_id = users.id AND t.total > $2 LEFT JOIN (SELECT user_id FROM bans) AS b ON b.user_id = users.id WHERE users.active = $3"
+	expectedArgs := []any{"2024-01-01", 100, true}
+
+	if sql != expectedExpr {
+		t.Errorf("expected SQL:\n%s\ngot:\n%s", expectedExpr, sql)
+	}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("expected args: %v, got: %v", expectedArgs, args)
+	}
+}
+
+func TestSubquery_OracleAliases(t *testing.T) {
+	sub := NewQuery().Select("user_id").From("orders").Where("status = ?", []any{"paid"})
+
+	qb := NewQuery().
+		WithDialect(Oracle).
+		Select("p.user_id").
+		FromSubquery(sub, "p").
+		JoinSubquery(NewQuery().Select("id").From("users"), "u", "u.id = p.user_id", nil)
+
+	sql, _, err := qb.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectedExpr := "SELECT p.user_id FROM (SELECT user_id FROM orders WHERE status = :1) p INNER JOIN (SELECT id FROM users) u ON u.id = p.user_id"
+	if sql != expectedExpr {
+		t.Errorf("expected SQL:\n%s\ngot:\n%s", expectedExpr, sql)
+	}
+
+	sql, _, err = qb.CountTotal().Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectedExpr = "SELECT COUNT(*) FROM (SELECT user_id FROM orders WHERE status = :1) p INNER JOIN (SELECT id FROM users) u ON u.id = p.user_id"
+	if sql != expectedExpr {
+		t.Errorf("expected SQL:\n%s\ngot:\n%s", expectedExpr, sql)
+	}
+
+	sql, _, err = sub.WithDialect(Oracle).Select("DISTINCT user_id").CountTotal().Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectedExpr = "SELECT COUNT(*) FROM (SELECT DISTINCT user_id FROM orders WHERE status = :1) subquery"
+	if sql != expectedExpr {
+		t.Errorf("expected SQL:\n%s\ngot:\n%s", expectedExpr, sql)
+	}
+}
+
+func TestSubquery_WhereOperands(t *testing.T) {
+	paid := NewQuery().Select("user_id").From("orders").Where("status = ?", []any{"paid"})
+
+	qb := NewQuery().
+		WithDialect(Postgres).
+		Select("id").
+		From("users").
+		Where("tenant_id = ?", []any{7}).
+		Where("id IN (?)", []any{paid}).
+		WhereCond(Or(
+			Exists(NewQuery().Select("1").From("admins").Where("admins.user_id = users.id", nil)),
+			NotExists(NewQuery().Select("1").From("bans").Where("bans.user_id = users.id AND bans.active = ?", []any{true})),
+		))
+
+	sql, args, err := qb.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedExpr := "SELECT id FROM users WHERE tenant_id = $1 AND id IN (SELECT user_id FROM orders WHERE status = $2) AND (EXISTS (SELECT 1 FROM admins WHERE admins.user_id = users.id) OR NOT EXISTS (SELECT 1 FROM bans WHERE bans.user_id = users.id AND bans.active = $3))"
+	expectedArgs := []any{7, "paid", true}
+
+	if sql != expectedExpr {
+		t.Errorf("expected SQL:\n%s\ngot:\n%s", expectedExpr, sql)
+	}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("expected args: %v, got: %v", expectedArgs, args)
+	}
+}
+
+func TestSubquery_CondOperands(t *testing.T) {
+	maxTotal := NewQuery().Select("MAX(total)").From("orders").Where("user_id = ?", []any{1})
+
+	qb := NewQuery().
+		Select("id").
+		From("orders").
+		WhereCond(And(
+			In("user_id", NewQuery().Select("id").From("users").Where("vip = ?", []any{true})),
+			Eq("total", maxTotal),
+		))
+
+	sql, args, err := qb.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedExpr := "SELECT id FROM orders WHERE user_id IN (SELECT id FROM users WHERE vip = ?) AND total = (SELECT MAX(total) FROM orders WHERE user_id = ?)"
+	expectedArgs := []any{true, 1}
+
+	if sql != expectedExpr {
+		t.Errorf("expected SQL:\n%s\ngot:\n%s", expectedExpr, sql)
+	}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("expected args: %v, got: %v", expectedArgs, args)
+	}
+}
+
+func TestSubquery_SelectSubquery(t *testing.T) {
+	orderCount := NewQuery().
+		Select("COUNT(*)").
+		From("orders").
+		Where("orders.user_id = users.id AND orders.status = ?", []any{"paid"})
+
+	qb := NewQuery().
+		WithDialect(Postgres).
+		Select("id", "name").
+		SelectSubquery(orderCount, "paid_orders").
+		From("users").
+		Where("active = ?", []any{true})
+
+	sql, args, err := qb.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedExpr := "SELECT id, name, (SELECT COUNT(*) FROM orders WHERE orders.user_id = users.id AND orders.status = $1) AS paid_orders FROM users WHERE active = $2"
+	expectedArgs := []any{"paid", true}
+
+	if sql != expectedExpr {
+		t.Errorf("expected SQL:\n%s\ngot:\n%s", expectedExpr, sql)
+	}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("expected args: %v, got: %v", expectedArgs, args)
+	}
+}
+
+func TestSubquery_InheritsQuoting(t *testing.T) {
+	sub := NewQuery().Select("user_id").From("order")
+
+	qb := NewQuery().
+		WithDialect(MySQL).
+		QuoteIdentifiers().
+		Select("id").
+		SelectSubquery(NewQuery().Select("COUNT(*)").From("order"), "order").
+		SelectExpr(RowNumber().Over(OrderBy("id")), "rank").
+		FromSubquery(sub, "o")
+
+	sql, _, err := qb.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedExpr := "SELECT `id`, (SELECT COUNT(*) FROM `order`) AS `order`, ROW_NUMBER() OVER (ORDER BY `id`) AS `rank` FROM (SELECT `user_id` FROM `order`) AS `o`"
+	if sql != expectedExpr {
+		t.Errorf("expected SQL:\n%s\ngot:\n%s", expectedExpr, sql)
+	}
+}
+
+func TestSubquery_PropagatesError(t *testing.T) {
+	qb := NewQuery().
+		Select("id").
+		From("users").
+		Where("id IN (?)", []any{NewQuery().Select("id")})
+
+	if _, _, err := qb.Build(); err == nil {
+		t.Fatal("expected error from invalid subquery")
+	}
+}
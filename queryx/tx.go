@@ -0,0 +1,178 @@
+package queryx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+)
+
+// TxBeginner starts transactions. It is satisfied by *sql.DB and *sql.Conn.
+type TxBeginner interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// TxOptions configures InTx.
+type TxOptions struct {
+	// Isolation and ReadOnly are passed to BeginTx.
+	Isolation sql.IsolationLevel
+	ReadOnly  bool
+	// MaxRetries is the number of times the whole transaction is run again
+	// after a retryable error. Zero disables retries.
+	MaxRetries int
+	// Retryable reports whether err is worth retrying. It defaults to
+	// IsRetryable.
+	Retryable func(err error) bool
+	// Backoff returns the delay before the given retry, starting at 1. It
+	// defaults to an exponential backoff from 10ms up to 1s, with jitter.
+	Backoff func(retry int) time.Duration
+}
+
+// InTx runs fn in a transaction, committing when fn returns nil and rolling
+// back when it returns an error or panics.
+//
+// db is usually a *sql.DB, possibly wrapped with NewRunner. When db is the
+// Runner passed to an enclosing InTx, fn runs within a savepoint of that
+// transaction instead, and only the savepoint is rolled back on error.
+// Retries apply to the outermost call only.
+func InTx(ctx context.Context, db Runner, opts *TxOptions, fn func(tx Runner) error) error {
+	if tx, ok := db.(*txRunner); ok {
+		return tx.withSavepoint(ctx, fn)
+	}
+	if opts == nil {
+		opts = &TxOptions{}
+	}
+
+	var dialect Dialect
+	if dr, ok := db.(dialectRunner); ok {
+		db, dialect = dr.Runner, dr.dialect
+	}
+	beginner, ok := db.(TxBeginner)
+	if !ok {
+		return fmt.Errorf("InTx: %T cannot begin transactions", db)
+	}
+
+	retryable := opts.Retryable
+	if retryable == nil {
+		retryable = IsRetryable
+	}
+	backoff := opts.Backoff
+	if backoff == nil {
+		backoff = defaultBackoff
+	}
+
+	for retry := 1; ; retry++ {
+		err := runTx(ctx, beginner, dialect, opts, fn)
+		if err == nil || retry > opts.MaxRetries || !retryable(err) {
+			return err
+		}
+
+		timer := time.NewTimer(backoff(retry))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return errors.Join(err, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+func runTx(ctx context.Context, db TxBeginner, dialect Dialect, opts *TxOptions, fn func(tx Runner) error) (err error) {
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{Isolation: opts.Isolation, ReadOnly: opts.ReadOnly})
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err := fn(&txRunner{Tx: tx, dialect: dialect}); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return errors.Join(err, rbErr)
+		}
+		return err
+	}
+	return tx.Commit()
+}
+
+// txRunner is the Runner passed to the function of InTx.
+type txRunner struct {
+	*sql.Tx
+	dialect    Dialect
+	savepoints int
+}
+
+func (t *txRunner) Dialect() Dialect {
+	return t.dialect
+}
+
+func (t *txRunner) withSavepoint(ctx context.Context, fn func(tx Runner) error) (err error) {
+	d := t.dialect
+	if d == nil {
+		d = Default
+	}
+	t.savepoints++
+	name := fmt.Sprintf("queryx_sp%d", t.savepoints)
+
+	save, release, rollback := "SAVEPOINT "+name, "RELEASE SAVEPOINT "+name, "ROLLBACK TO SAVEPOINT "+name
+	switch {
+	case d.Supports(FeatureSaveTransaction):
+		save, release, rollback = "SAVE TRANSACTION "+name, "", "ROLLBACK TRANSACTION "+name
+	case !d.Supports(FeatureReleaseSavepoint):
+		release = ""
+	}
+
+	if _, err := t.ExecContext(ctx, save); err != nil {
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_, _ = t.ExecContext(ctx, rollback)
+			panic(p)
+		}
+	}()
+
+	if err := fn(t); err != nil {
+		if _, rbErr := t.ExecContext(ctx, rollback); rbErr != nil {
+			return errors.Join(err, rbErr)
+		}
+		return err
+	}
+	if release != "" {
+		_, err = t.ExecContext(ctx, release)
+	}
+	return err
+}
+
+// IsRetryable reports whether err is a serialization failure or a deadlock
+// that usually succeeds when the transaction is run again. It recognizes
+// drivers whose errors report a SQLSTATE through a SQLState method, such as
+// pgx and lib/pq (40001 and 40P01), and SQL Server errors reporting number
+// 1205 through SQLErrorNumber.
+func IsRetryable(err error) bool {
+	var state interface{ SQLState() string }
+	if errors.As(err, &state) {
+		switch state.SQLState() {
+		case "40001", "40P01":
+			return true
+		}
+	}
+	var number interface{ SQLErrorNumber() int32 }
+	if errors.As(err, &number) {
+		return number.SQLErrorNumber() == 1205
+	}
+	return false
+}
+
+func defaultBackoff(retry int) time.Duration {
+	d := 10 * time.Millisecond << min(retry-1, 7)
+	d = min(d, time.Second)
+	return d/2 + rand.N(d/2)
+}
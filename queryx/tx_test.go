@@ -0,0 +1,185 @@
+package queryx
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type sqlStateError string
+
+func (e sqlStateError) Error() string    { return "sqlstate " + string(e) }
+func (e sqlStateError) SQLState() string { return string(e) }
+
+func noBackoff(int) time.Duration { return 0 }
+
+func TestInTx_Commit(t *testing.T) {
+	db, fdb := newFakeDB(t, nil)
+
+	err := InTx(context.Background(), NewRunner(db, Postgres), nil, func(tx Runner) error {
+		_, err := NewQuery().Update("users", nil).Set("active", true).Where("id = ?", []any{1}).ExecContext(context.Background(), tx)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{"BEGIN", "UPDATE users SET active = $1 WHERE id = $2", "COMMIT"}
+	if !reflect.DeepEqual(fdb.queries(), expected) {
+		t.Errorf("expected %q, got %q", expected, fdb.queries())
+	}
+}
+
+func TestInTx_RollbackOnErrorAndPanic(t *testing.T) {
+	db, fdb := newFakeDB(t, nil)
+	fnErr := errors.New("boom")
+
+	err := InTx(context.Background(), db, nil, func(tx Runner) error { return fnErr })
+	if !errors.Is(err, fnErr) {
+		t.Fatalf("expected %v, got %v", fnErr, err)
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected the panic to propagate")
+			}
+		}()
+		_ = InTx(context.Background(), db, nil, func(tx Runner) error { panic("boom") })
+	}()
+
+	expected := []string{"BEGIN", "ROLLBACK", "BEGIN", "ROLLBACK"}
+	if !reflect.DeepEqual(fdb.queries(), expected) {
+		t.Errorf("expected %q, got %q", expected, fdb.queries())
+	}
+}
+
+func TestInTx_Savepoints(t *testing.T) {
+	cases := []struct {
+		dialect  Dialect
+		expected []string
+	}{
+		{
+			Postgres,
+			[]string{
+				"BEGIN",
+				"SAVEPOINT queryx_sp1", "RELEASE SAVEPOINT queryx_sp1",
+				"SAVEPOINT queryx_sp2", "ROLLBACK TO SAVEPOINT queryx_sp2",
+				"COMMIT",
+			},
+		},
+		{
+			SQLServer,
+			[]string{
+				"BEGIN",
+				"SAVE TRANSACTION queryx_sp1",
+				"SAVE TRANSACTION queryx_sp2", "ROLLBACK TRANSACTION queryx_sp2",
+				"COMMIT",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		db, fdb := newFakeDB(t, nil)
+		nestedErr := errors.New("nested")
+
+		err := InTx(context.Background(), NewRunner(db, c.dialect), nil, func(tx Runner) error {
+			if err := InTx(context.Background(), tx, nil, func(Runner) error { return nil }); err != nil {
+				return err
+			}
+			if err := InTx(context.Background(), tx, nil, func(Runner) error { return nestedErr }); !errors.Is(err, nestedErr) {
+				return fmt.Errorf("expected nested error, got %v", err)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", c.dialect.Name(), err)
+		}
+		if !reflect.DeepEqual(fdb.queries(), c.expected) {
+			t.Errorf("%s: expected %q, got %q", c.dialect.Name(), c.expected, fdb.queries())
+		}
+	}
+}
+
+func TestInTx_RetrySerializationFailure(t *testing.T) {
+	failures := 2
+	db, fdb := newFakeDB(t, func(query string, args []driver.Value) (*fakeResult, error) {
+		if strings.HasPrefix(query, "UPDATE") && failures > 0 {
+			failures--
+			return nil, sqlStateError("40001")
+		}
+		return &fakeResult{}, nil
+	})
+
+	runs := 0
+	err := InTx(context.Background(), db, &TxOptions{MaxRetries: 3, Backoff: noBackoff}, func(tx Runner) error {
+		runs++
+		_, err := NewQuery().Update("accounts", nil).Decrement("balance", 10).Where("id = ?", []any{1}).ExecContext(context.Background(), tx)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if runs != 3 {
+		t.Errorf("expected 3 runs, got %d", runs)
+	}
+
+	queries := fdb.queries()
+	if queries[len(queries)-1] != "COMMIT" || strings.Count(strings.Join(queries, ";"), "ROLLBACK") != 2 {
+		t.Errorf("unexpected statements: %q", queries)
+	}
+}
+
+func TestInTx_RetryLimits(t *testing.T) {
+	db, _ := newFakeDB(t, nil)
+
+	runs := 0
+	deadlock := fmt.Errorf("update: %w", sqlStateError("40P01"))
+	err := InTx(context.Background(), db, &TxOptions{MaxRetries: 2, Backoff: noBackoff}, func(Runner) error {
+		runs++
+		return deadlock
+	})
+	if !errors.Is(err, deadlock) || runs != 3 {
+		t.Errorf("expected deadlock after 3 runs, got %v after %d", err, runs)
+	}
+
+	runs = 0
+	err = InTx(context.Background(), db, &TxOptions{MaxRetries: 2, Backoff: noBackoff}, func(Runner) error {
+		runs++
+		return sqlStateError("23505")
+	})
+	if err == nil || runs != 1 {
+		t.Errorf("expected a single run for a non-retryable error, got %d", runs)
+	}
+
+	runs = 0
+	custom := errors.New("try again")
+	err = InTx(context.Background(), db, &TxOptions{
+		MaxRetries: 1,
+		Backoff:    noBackoff,
+		Retryable:  func(err error) bool { return errors.Is(err, custom) },
+	}, func(Runner) error {
+		runs++
+		if runs == 1 {
+			return custom
+		}
+		return nil
+	})
+	if err != nil || runs != 2 {
+		t.Errorf("expected success on the second run, got %v after %d", err, runs)
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	if !IsRetryable(sqlStateError("40001")) || !IsRetryable(sqlStateError("40P01")) {
+		t.Error("expected serialization failures and deadlocks to be retryable")
+	}
+	if IsRetryable(sqlStateError("23505")) || IsRetryable(errors.New("boom")) {
+		t.Error("expected other errors not to be retryable")
+	}
+}
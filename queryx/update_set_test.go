@@ -0,0 +1,102 @@
+package queryx
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUpdateSet(t *testing.T) {
+	latest := NewQuery().Select("MAX(created_at)").From("visits").Where("visits.page_id = pages.id", nil)
+
+	qb := NewQuery().
+		WithDialect(Postgres).
+		Update("pages", []string{"title"}).
+		Values("Home").
+		Set("status", "published").
+		SetExpr("updated_at", "NOW()", nil).
+		SetExpr("slug", "lower(title)", nil).
+		Increment("views", 1).
+		Decrement("credits", 2).
+		Set("last_visit", latest).
+		Where("id = ?", []any{7})
+
+	sql, args, err := qb.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedExpr := "UPDATE pages SET title = $1, status = $2, updated_at = NOW(), slug = lower(title), views = views + $3, credits = credits - $4, last_visit = (SELECT MAX(created_at) FROM visits WHERE visits.page_id = pages.id) WHERE id = $5"
+	expectedArgs := []any{"Home", "published", 1, 2, 7}
+
+	if sql != expectedExpr {
+		t.Errorf("expected SQL:\n%s\ngot:\n%s", expectedExpr, sql)
+	}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("expected args: %v, got: %v", expectedArgs, args)
+	}
+}
+
+func TestUpdateSet_WithoutColumns(t *testing.T) {
+	qb := NewQuery().
+		WithDialect(MySQL).
+		QuoteIdentifiers().
+		Update("order", nil).
+		Increment("count", 1).
+		SetExpr("a", "b", nil).
+		Where("id = ?", []any{1})
+
+	sql, args, err := qb.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedExpr := "UPDATE `order` SET `count` = `count` + ?, `a` = b WHERE id = ?"
+	expectedArgs := []any{1, 1}
+
+	if sql != expectedExpr {
+		t.Errorf("expected SQL:\n%s\ngot:\n%s", expectedExpr, sql)
+	}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("expected args: %v, got: %v", expectedArgs, args)
+	}
+}
+
+func TestUpdateSet_Errors(t *testing.T) {
+	cases := []struct {
+		name string
+		qb   *QueryBuilder
+	}{
+		{"no assignments", NewQuery().Update("t", nil).Where("id = ?", []any{1})},
+		{"columns without values", NewQuery().Update("t", []string{"a"}).Set("b", 1)},
+	}
+
+	for _, c := range cases {
+		if _, _, err := c.qb.Build(); err == nil {
+			t.Errorf("%s: expected error", c.name)
+		}
+	}
+}
+
+func TestUpdateSet_SliceValues(t *testing.T) {
+	qb := NewQuery().
+		WithDialect(Postgres).
+		Update("posts", []string{"tags"}).
+		Values([]string{"a", "b"}).
+		Set("scores", []int{1, 2}).
+		Where("id IN (?)", []any{[]int{7, 8}})
+
+	sql, args, err := qb.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedExpr := "UPDATE posts SET tags = $1, scores = $2 WHERE id IN ($3, $4)"
+	expectedArgs := []any{[]string{"a", "b"}, []int{1, 2}, 7, 8}
+
+	if sql != expectedExpr {
+		t.Errorf("expected SQL:\n%s\ngot:\n%s", expectedExpr, sql)
+	}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("expected args: %v, got: %v", expectedArgs, args)
+	}
+}
@@ -0,0 +1,104 @@
+package queryx
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUpsert_OnConflictDoUpdate(t *testing.T) {
+	qb := NewQuery().
+		WithDialect(Postgres).
+		Insert("users", []string{"email", "name", "logins"}).
+		Values("a@b.c", "Ann", 1).
+		OnConflict("email").
+		DoUpdateSet("name").
+		DoUpdateSetExpr("logins", "users.logins + ?", []any{Excluded("logins")}).
+		DoUpdateWhere("users.locked = ?", []any{false})
+
+	sql, args, err := qb.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedExpr := "INSERT INTO users (email, name, logins) VALUES ($1, $2, $3) ON CONFLICT (email) DO UPDATE SET name = EXCLUDED.name, logins = users.logins + EXCLUDED.logins WHERE users.locked = $4"
+	expectedArgs := []any{"a@b.c", "Ann", 1, false}
+
+	if sql != expectedExpr {
+		t.Errorf("expected SQL:\n%s\ngot:\n%s", expectedExpr, sql)
+	}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("expected args: %v, got: %v", expectedArgs, args)
+	}
+}
+
+func TestUpsert_OnConflictDoNothing(t *testing.T) {
+	qb := NewQuery().
+		WithDialect(SQLite).
+		QuoteIdentifiers().
+		Insert("events", []string{"id", "payload"}).
+		MultiValues([][]any{{1, "a"}, {2, "b"}}).
+		OnConflict("id").
+		DoNothing()
+
+	sql, args, err := qb.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedExpr := `INSERT INTO "events" ("id", "payload") VALUES (?, ?), (?, ?) ON CONFLICT ("id") DO NOTHING`
+	expectedArgs := []any{1, "a", 2, "b"}
+
+	if sql != expectedExpr {
+		t.Errorf("expected SQL:\n%s\ngot:\n%s", expectedExpr, sql)
+	}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("expected args: %v, got: %v", expectedArgs, args)
+	}
+}
+
+func TestUpsert_OnDuplicateKeyUpdate(t *testing.T) {
+	qb := NewQuery().
+		WithDialect(MySQL).
+		Insert("counters", []string{"name", "hits"}).
+		Values("home", 1).
+		OnDuplicateKeyUpdate("name").
+		OnDuplicateKeyUpdateExpr("hits", "hits + ?", []any{Excluded("hits")})
+
+	sql, args, err := qb.Build()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedExpr := "INSERT INTO counters (name, hits) VALUES (?, ?) ON DUPLICATE KEY UPDATE name = VALUES(name), hits = hits + VALUES(hits)"
+	expectedArgs := []any{"home", 1}
+
+	if sql != expectedExpr {
+		t.Errorf("expected SQL:\n%s\ngot:\n%s", expectedExpr, sql)
+	}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("expected args: %v, got: %v", expectedArgs, args)
+	}
+}
+
+func TestUpsert_Errors(t *testing.T) {
+	insert := func(d Dialect) *QueryBuilder {
+		return NewQuery().WithDialect(d).Insert("t", []string{"id"}).Values(1)
+	}
+
+	cases := []struct {
+		name string
+		qb   *QueryBuilder
+	}{
+		{"on conflict on mysql", insert(MySQL).OnConflict("id").DoNothing()},
+		{"on duplicate key on postgres", insert(Postgres).OnDuplicateKeyUpdate("id")},
+		{"on conflict without action", insert(Postgres).OnConflict("id")},
+		{"do update without target", insert(Postgres).DoUpdateSet("id")},
+		{"do nothing and do update", insert(Postgres).OnConflict("id").DoNothing().DoUpdateSet("id")},
+	}
+
+	for _, c := range cases {
+		if _, _, err := c.qb.Build(); err == nil {
+			t.Errorf("%s: expected error", c.name)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package queryx
+
+import (
+	"fmt"
+	"strings"
+)
+
+// WindowFunc is a call to a window function. Over computes it over a window
+// and returns an expression for SelectExpr:
+//
+//	qb.SelectExpr(queryx.RowNumber().Over(
+//		queryx.PartitionBy("user_id"),
+//		queryx.OrderBy("created_at DESC"),
+//	), "rn")
+type WindowFunc struct {
+	name   string
+	column string
+	// extra follows the column in the argument list, as in "LAG(price, 1)".
+	extra string
+	args  []any
+}
+
+// RowNumber numbers the rows of each partition from 1.
+func RowNumber() WindowFunc {
+	return WindowFunc{name: "ROW_NUMBER"}
+}
+
+// Rank ranks the rows of each partition, with gaps after ties.
+func Rank() WindowFunc {
+	return WindowFunc{name: "RANK"}
+}
+
+// DenseRank ranks the rows of each partition, without gaps after ties.
+func DenseRank() WindowFunc {
+	return WindowFunc{name: "DENSE_RANK"}
+}
+
+// Lag returns column from the row offset rows before the current one, or
+// def when there is none. A nil def leaves the default of NULL.
+func Lag(column string, offset int, def any) WindowFunc {
+	return offsetFunc("LAG", column, offset, def)
+}
+
+// Lead returns column from the row offset rows after the current one, or
+// def when there is none. A nil def leaves the default of NULL.
+func Lead(column string, offset int, def any) WindowFunc {
+	return offsetFunc("LEAD", column, offset, def)
+}
+
+func offsetFunc(name, column string, offset int, def any) WindowFunc {
+	f := WindowFunc{name: name, column: column, extra: fmt.Sprintf(", %d", offset)}
+	if def != nil {
+		f.extra += ", ?"
+		f.args = []any{def}
+	}
+	return f
+}
+
+func (f WindowFunc) appendSQL(qb *QueryBuilder, b *strings.Builder, args []any) ([]any, error) {
+	b.WriteString(f.name + "(")
+	if f.column != "" {
+		b.WriteString(quoteColumn(qb, f.column))
+	}
+	b.WriteString(f.extra + ")")
+	return append(args, f.args...), nil
+}
+
+// Over computes f over the window described by parts, rendered as
+// "f() OVER (PARTITION BY ... ORDER BY ... ROWS BETWEEN ...)".
+func (f WindowFunc) Over(parts ...WindowPart) Expr {
+	return windowExpr{call: f, spec: newWindowSpec(parts)}
+}
+
+// OverWindow computes f over a window declared with QueryBuilder.Window,
+// rendered as "f() OVER name".
+func (f WindowFunc) OverWindow(name string) Expr {
+	return windowExpr{call: f, name: name}
+}
+
+type windowExpr struct {
+	call Expr
+	name string
+	spec windowSpec
+}
+
+func (w windowExpr) appendSQL(qb *QueryBuilder, b *strings.Builder, args []any) ([]any, error) {
+	args, err := w.call.appendSQL(qb, b, args)
+	if err != nil {
+		return nil, err
+	}
+	if w.name != "" {
+		b.WriteString(" OVER " + quoteColumn(qb, w.name))
+	} else {
+		b.WriteString(" OVER (" + w.spec.sql(qb) + ")")
+	}
+	return args, nil
+}
+
+// WindowPart describes a window: its partitions, ordering and frame.
+type WindowPart func(*windowSpec)
+
+type windowSpec struct {
+	partitionBy []string
+	orderBy     []string
+	frame       string
+}
+
+func newWindowSpec(parts []WindowPart) windowSpec {
+	var spec windowSpec
+	for _, part := range parts {
+		part(&spec)
+	}
+	return spec
+}
+
+func (s windowSpec) sql(qb *QueryBuilder) string {
+	var clauses []string
+	if len(s.partitionBy) > 0 {
+		clauses = append(clauses, "PARTITION BY "+strings.Join(quoteColumns(qb, s.partitionBy), ", "))
+	}
+	if len(s.orderBy) > 0 {
+		terms := make([]string, len(s.orderBy))
+		for i, term := range s.orderBy {
+			terms[i] = quoteOrderBy(qb, term)
+		}
+		clauses = append(clauses, "ORDER BY "+strings.Join(terms, ", "))
+	}
+	if s.frame != "" {
+		clauses = append(clauses, s.frame)
+	}
+	return strings.Join(clauses, " ")
+}
+
+// PartitionBy splits the rows of a window into groups of equal columns.
+func PartitionBy(columns ...string) WindowPart {
+	return func(s *windowSpec) {
+		s.partitionBy = append(s.partitionBy, columns...)
+	}
+}
+
+// OrderBy orders the rows of a window partition, with terms written as for
+// QueryBuilder.OrderBy.
+func OrderBy(terms ...string) WindowPart {
+	return func(s *windowSpec) {
+		s.orderBy = append(s.orderBy, terms...)
+	}
+}
+
+// FrameBound is a bound of a window frame.
+type FrameBound string
+
+const (
+	UnboundedPreceding FrameBound = "UNBOUNDED PRECEDING"
+	CurrentRow         FrameBound = "CURRENT ROW"
+	UnboundedFollowing FrameBound = "UNBOUNDED FOLLOWING"
+)
+
+// Preceding bounds a frame n rows, or n units of the order by value for
+// Range, before the current row.
+func Preceding(n int) FrameBound {
+	return FrameBound(fmt.Sprintf("%d PRECEDING", n))
+}
+
+// Following bounds a frame n rows, or n units of the order by value for
+// Range, after the current row.
+func Following(n int) FrameBound {
+	return FrameBound(fmt.Sprintf("%d FOLLOWING", n))
+}
+
+// Rows limits the window to a frame of rows, as in
+// "ROWS BETWEEN 6 PRECEDING AND CURRENT ROW".
+func Rows(start, end FrameBound) WindowPart {
+	return frame("ROWS", start, end)
+}
+
+// Range limits the window to a frame of rows whose order by value is within
+// the bounds of the current one.
+func Range(start, end FrameBound) WindowPart {
+	return frame("RANGE", start, end)
+}
+
+func frame(unit string, start, end FrameBound) WindowPart {
+	return func(s *windowSpec) {
+		s.frame = unit + " BETWEEN " + string(start) + " AND " + string(end)
+	}
+}
+
+// Window declares a named window, rendered in a WINDOW clause after HAVING,
+// for use with WindowFunc.OverWindow.
+func (qb *QueryBuilder) Window(name string, parts ...WindowPart) *QueryBuilder {
+	qb.windows = append(qb.windows, namedWindow{name: name, spec: newWindowSpec(parts)})
+	return qb
+}
+
+type namedWindow struct {
+	name string
+	spec windowSpec
+}
+
+func buildWindows(qb *QueryBuilder, b *strings.Builder, args []any) []any {
+	if len(qb.windows) == 0 {
+		return args
+	}
+	b.WriteString(" WINDOW ")
+	for i, w := range qb.windows {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(quoteColumn(qb, w.name) + " AS (" + w.spec.sql(qb) + ")")
+	}
+	return args
+}
@@ -0,0 +1,95 @@
+package queryx
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWindowFunctions(t *testing.T) {
+	cases := []struct {
+		name         string
+		qb           *QueryBuilder
+		expectedExpr string
+		expectedArgs []any
+	}{
+		{
+			"latest row per group",
+			NewQuery().
+				WithDialect(Postgres).
+				Select("*").
+				FromSubquery(
+					NewQuery().
+						Select("id", "user_id", "created_at").
+						SelectExpr(RowNumber().Over(PartitionBy("user_id"), OrderBy("created_at DESC")), "rn").
+						From("orders").
+						Where("status = ?", []any{"paid"}),
+					"ranked",
+				).
+				Where("rn = ?", []any{1}),
+			"SELECT * FROM (SELECT id, user_id, created_at, ROW_NUMBER() OVER (PARTITION BY user_id ORDER BY created_at DESC) AS rn FROM orders WHERE status = $1) AS ranked WHERE rn = $2",
+			[]any{"paid", 1},
+		},
+		{
+			"ranking",
+			NewQuery().
+				Select("name").
+				SelectExpr(Rank().Over(OrderBy("score DESC")), "rank").
+				SelectExpr(DenseRank().Over(PartitionBy("team", "season"), OrderBy("score DESC")), "team_rank").
+				From("players"),
+			"SELECT name, RANK() OVER (ORDER BY score DESC) AS rank, DENSE_RANK() OVER (PARTITION BY team, season ORDER BY score DESC) AS team_rank FROM players",
+			nil,
+		},
+		{
+			"lag and lead",
+			NewQuery().
+				WithDialect(MySQL).
+				QuoteIdentifiers().
+				Select("day").
+				SelectExpr(Lag("price", 1, 0).Over(OrderBy("day")), "previous").
+				SelectExpr(Lead("price", 2, nil).Over(OrderBy("day")), "after_next").
+				From("prices"),
+			"SELECT `day`, LAG(`price`, 1, ?) OVER (ORDER BY `day`) AS `previous`, LEAD(`price`, 2) OVER (ORDER BY `day`) AS `after_next` FROM `prices`",
+			[]any{0},
+		},
+		{
+			"frames",
+			NewQuery().
+				Select("day").
+				SelectExpr(Sum("amount").Over(OrderBy("day"), Rows(Preceding(6), CurrentRow)), "weekly").
+				SelectExpr(Sum("amount").Over(PartitionBy("account_id"), Range(UnboundedPreceding, Following(0))), "running").
+				From("payments"),
+			"SELECT day, SUM(amount) OVER (ORDER BY day ROWS BETWEEN 6 PRECEDING AND CURRENT ROW) AS weekly, SUM(amount) OVER (PARTITION BY account_id RANGE BETWEEN UNBOUNDED PRECEDING AND 0 FOLLOWING) AS running FROM payments",
+			nil,
+		},
+		{
+			"named window",
+			NewQuery().
+				WithDialect(Postgres).
+				Select("team").
+				SelectExpr(Sum("points").OverWindow("w"), "total").
+				SelectExpr(RowNumber().OverWindow("w"), "position").
+				From("results").
+				GroupBy("team", "points").
+				Having("COUNT(*) > ?", []any{1}).
+				Window("w", PartitionBy("team"), OrderBy("points DESC")).
+				OrderBy("team"),
+			"SELECT team, SUM(points) OVER w AS total, ROW_NUMBER() OVER w AS position FROM results GROUP BY team, points HAVING COUNT(*) > $1 WINDOW w AS (PARTITION BY team ORDER BY points DESC) ORDER BY team",
+			[]any{1},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			expr, args, err := c.qb.Build()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if expr != c.expectedExpr {
+				t.Errorf("expected SQL:\n%s\ngot:\n%s", c.expectedExpr, expr)
+			}
+			if !reflect.DeepEqual(args, c.expectedArgs) {
+				t.Errorf("expected args %v, got %v", c.expectedArgs, args)
+			}
+		})
+	}
+}